@@ -0,0 +1,46 @@
+package errs
+
+// Builder constructs an errs.Err fluently, e.g
+//
+//	errs.Build().Code("NOT_FOUND").Status(404).Info("ID", id).Public("Not found").Err()
+type Builder struct {
+	code       string
+	httpStatus int
+	info       Info
+	publicMsg  []interface{}
+}
+
+// Build returns a new Builder
+func Build() *Builder {
+	return &Builder{info: Info{}}
+}
+
+// Code sets the error code. See Err.Code
+func (b *Builder) Code(code string) *Builder {
+	b.code = code
+	return b
+}
+
+// Status sets the HTTP status. See Err.HTTPStatus
+func (b *Builder) Status(status int) *Builder {
+	b.httpStatus = status
+	return b
+}
+
+// Info sets the info value for the given key. See Err.Info
+func (b *Builder) Info(key string, val interface{}) *Builder {
+	b.info[key] = val
+	return b
+}
+
+// Public appends the given parts to the public message. See Err.PublicMsg
+func (b *Builder) Public(publicMsg ...interface{}) *Builder {
+	b.publicMsg = append(b.publicMsg, publicMsg...)
+	return b
+}
+
+// Err creates the errs.Err. Its stack is captured at the call to Err.
+// The error gets its own copy of the info, so the Builder may be reused.
+func (b *Builder) Err() Err {
+	return newErr(callers(0), nil, false, copyInfo(b.info), b.publicMsg, withCode(b.code), withHTTPStatus(b.httpStatus))
+}
@@ -0,0 +1,28 @@
+package errs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestBuild(t *testing.T) {
+	err := errs.Build().Code("X").Status(404).Info("ID", 7).Public("Not", "found").Err()
+	assert(t, err.Code() == "X")
+	assert(t, err.HTTPStatus() == 404)
+	assert(t, err.Info("ID") == 7)
+	assert(t, err.PublicMsg() == "Not found")
+	frames := err.StackFrames()
+	assert(t, strings.HasSuffix(frames[0].Func, ".TestBuild"), "Expected top frame to be the caller of Err", frames[0].Func)
+}
+
+func TestBuildReuse(t *testing.T) {
+	b := errs.Build().Info("a", 1)
+	e1 := b.Err()
+	b.Info("b", 2)
+	e2 := b.Err()
+	e2.WithInfo("c", 3)
+	assert(t, !e1.HasInfo("b") && !e1.HasInfo("c"), "Expected errors to not share info with the Builder", e1.AllInfo())
+	assert(t, e2.Info("a") == 1 && e2.Info("b") == 2)
+}
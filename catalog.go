@@ -0,0 +1,59 @@
+package errs
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Register adds an error code to the catalog, with the HTTP status and
+// default public message of errors created for it by FromCode. Codes are
+// usually registered in package-level vars or init functions, so that
+// the catalog is the single source of truth for codes and messages:
+//
+//	var ErrEmailTaken = errs.Register("USER_EMAIL_TAKEN", 409, "That email is already taken")
+//
+// Register panics if the code is empty or already registered. It returns the code.
+func Register(code string, httpStatus int, publicMsg string) string {
+	if code == "" {
+		panic("errs: Register called with an empty code")
+	}
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	if _, exists := catalog[code]; exists {
+		panic(fmt.Sprintf("errs: code %q is already registered", code))
+	}
+	catalog[code] = catalogEntry{httpStatus, publicMsg}
+	return code
+}
+
+// FromCode creates a new Err with the given code, and the HTTP status
+// and default public message given to Register for it. If the code
+// is not registered, the Err only has the code, like errs.NewWithCode,
+// e.g `return errs.FromCode("USER_EMAIL_TAKEN", errs.Info{"Email": email})`
+func FromCode(code string, info Info) Err {
+	catalogMu.RLock()
+	entry, exists := catalog[code]
+	catalogMu.RUnlock()
+	if !exists {
+		return newErr(callers(0), nil, false, info, nil, withCode(code))
+	}
+	var publicMsg []interface{}
+	if entry.publicMsg != "" {
+		publicMsg = []interface{}{entry.publicMsg}
+	}
+	return newErr(callers(0), nil, false, info, publicMsg, withCode(code), withHTTPStatus(entry.httpStatus))
+}
+
+// Internal
+///////////
+
+// catalogEntry is the registered status and message for a code
+type catalogEntry struct {
+	httpStatus int
+	publicMsg  string
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = map[string]catalogEntry{}
+)
@@ -0,0 +1,32 @@
+package errs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+var codeEmailTaken = errs.Register("TEST_EMAIL_TAKEN", 409, "That email is already taken")
+
+func TestFromCode(t *testing.T) {
+	err := errs.FromCode(codeEmailTaken, errs.Info{"Email": "foo@bar.com"})
+	assert(t, err.Code() == "TEST_EMAIL_TAKEN", err.Code())
+	assert(t, err.HTTPStatus() == 409, err.HTTPStatus())
+	assert(t, err.PublicMsg() == "That email is already taken", err.PublicMsg())
+	assert(t, err.Info("Email") == "foo@bar.com")
+	assert(t, strings.Contains(string(err.Stack()), "TestFromCode"), "Expected stack to start at caller")
+
+	err = errs.FromCode("TEST_UNREGISTERED", nil)
+	assert(t, err.Code() == "TEST_UNREGISTERED")
+	assert(t, err.HTTPStatus() == 500 && err.PublicMsg() == "", "Expected defaults for unregistered code")
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	defer func() {
+		recovered := recover()
+		assert(t, recovered != nil, "Expected duplicate registration to panic")
+		assert(t, strings.Contains(recovered.(string), "TEST_EMAIL_TAKEN"), recovered)
+	}()
+	errs.Register("TEST_EMAIL_TAKEN", 400, "Again")
+}
@@ -0,0 +1,94 @@
+package errs
+
+import (
+	"errors"
+	"reflect"
+)
+
+// As finds the first error in err's chain that is assignable to T,
+// and returns it. It is a generic version of errors.As:
+//
+//	if pqErr, ok := errs.As[*pq.Error](err); ok { ... }
+func As[T error](err error) (T, bool) {
+	var target T
+	if errors.As(err, &target) {
+		return target, true
+	}
+	return target, false
+}
+
+// GetInfo finds the first errs.Err in err's chain whose info contains key,
+// and returns its value if it is a T. The bool reports whether the key
+// was found with a value of type T.
+//
+//	userID, ok := errs.GetInfo[int64](err, "UserID")
+func GetInfo[T any](err error, key string) (T, bool) {
+	var zero T
+	for ; err != nil; err = errors.Unwrap(err) {
+		errsErr, isErr := IsErr(err)
+		if !isErr {
+			continue
+		}
+		if _, hasKey := errsErr.AllInfo()[key]; hasKey {
+			val, isT := errsErr.Info(key).(T)
+			return val, isT
+		}
+	}
+	return zero, false
+}
+
+// Walk calls fn for each error in err's chain, from the outermost error
+// to the root cause, and stops as soon as fn returns false. The chain is
+// followed through both `Unwrap() error` and `Unwrap() []error`, where
+// joined errors are walked depth-first in order. Each pointer error is
+// only visited once, so self-referential chains do not loop forever.
+//
+//	errs.Walk(err, func(err error) bool { log.Println(err); return true })
+func Walk(err error, fn func(error) bool) {
+	walk(err, fn, map[error]bool{})
+}
+
+// IsCode reports whether any errs.Err in err's chain has the given code,
+// e.g `if errs.IsCode(err, "USER_EMAIL_TAKEN") { ... }`
+func IsCode(err error, code string) bool {
+	found := false
+	Walk(err, func(err error) bool {
+		if errsErr, isErr := IsErr(err); isErr && errsErr.Code() == code {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// Internal
+///////////
+
+// Walk err's chain, and return false if fn returned false
+func walk(err error, fn func(error) bool, visited map[error]bool) bool {
+	for err != nil {
+		if reflect.ValueOf(err).Kind() == reflect.Pointer {
+			if visited[err] {
+				return true
+			}
+			visited[err] = true
+		}
+		if !fn(err) {
+			return false
+		}
+		switch unwrapper := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, child := range unwrapper.Unwrap() {
+				if !walk(child, fn, visited) {
+					return false
+				}
+			}
+			return true
+		case interface{ Unwrap() error }:
+			err = unwrapper.Unwrap()
+		default:
+			return true
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestAs(t *testing.T) {
+	_, openErr := os.Open("/does/not/exist")
+	err := errs.Wrap(fmt.Errorf("opening: %w", errs.Wrap(openErr, nil)), nil)
+	pathErr, ok := errs.As[*os.PathError](err)
+	assert(t, ok, "Expected to find *os.PathError")
+	assert(t, pathErr.Path == "/does/not/exist")
+
+	err = errs.Wrap(fmt.Errorf("dialing: %w", fakeNetErr{timeout: true}), nil)
+	netErr, ok := errs.As[net.Error](err)
+	assert(t, ok, "Expected to find net.Error")
+	assert(t, netErr.Timeout())
+
+	_, ok = errs.As[*os.LinkError](err)
+	assert(t, !ok, "Expected not to find *os.LinkError")
+}
+
+func TestGetInfo(t *testing.T) {
+	inner := errs.New(errs.Info{"UserID": int64(7), "Name": "Bob"})
+	err := errs.Wrap(fmt.Errorf("wrapped: %w", inner), errs.Info{"Name": 8})
+
+	userID, ok := errs.GetInfo[int64](err, "UserID")
+	assert(t, ok && userID == 7, "Expected to find UserID in the inner error")
+	_, ok = errs.GetInfo[int64](err, "Name")
+	assert(t, !ok, "Expected the first Name, from the outer error, to be the wrong type")
+	name, ok := errs.GetInfo[string](inner, "Name")
+	assert(t, ok && name == "Bob")
+	_, ok = errs.GetInfo[string](err, "Missing")
+	assert(t, !ok, "Expected a missing key to not be found")
+	_, ok = errs.GetInfo[string](nil, "Name")
+	assert(t, !ok)
+}
+
+func TestWalk(t *testing.T) {
+	root := errors.New("root")
+	inner := errs.Wrap(root, nil)
+	other := errors.New("other")
+	err := errs.Join(fmt.Errorf("wrapped: %w", inner), other)
+
+	var visited []error
+	errs.Walk(err, func(err error) bool {
+		visited = append(visited, err)
+		return true
+	})
+	assert(t, len(visited) == 5, "Expected 5 errors", len(visited))
+	assert(t, visited[0] == err)
+	assert(t, visited[2] == inner)
+	assert(t, visited[3] == root)
+	assert(t, visited[4] == other)
+
+	var count int
+	errs.Walk(err, func(err error) bool {
+		count++
+		return err != inner
+	})
+	assert(t, count == 3, "Expected walk to stop at inner", count)
+
+	loop := &loopErr{}
+	loop.next = loop
+	count = 0
+	errs.Walk(loop, func(err error) bool { count++; return true })
+	assert(t, count == 1, "Expected self-referential error to be visited once", count)
+
+	errs.Walk(nil, func(err error) bool { t.Fatal("Expected no call for nil"); return true })
+}
+
+func TestIsCode(t *testing.T) {
+	err := errs.Wrap(fmt.Errorf("wrapped: %w", errs.NewWithCode("USER_EMAIL_TAKEN", nil)), nil)
+	assert(t, errs.IsCode(err, "USER_EMAIL_TAKEN"), "Expected matching code")
+	assert(t, !errs.IsCode(err, "USER_NOT_FOUND"), "Expected non-matching code")
+	assert(t, !errs.IsCode(errors.New("plain"), "USER_EMAIL_TAKEN"), "Expected non-errs error not to match")
+	assert(t, errs.IsCode(errs.Join(errors.New("plain"), err), "USER_EMAIL_TAKEN"), "Expected joined error to match")
+}
+
+type loopErr struct{ next error }
+
+func (l *loopErr) Error() string { return "loop" }
+func (l *loopErr) Unwrap() error { return l.next }
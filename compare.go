@@ -0,0 +1,46 @@
+package errs
+
+import "reflect"
+
+// Implements Err
+func (e *err) Equal(other Err) bool {
+	return e.equal(e.PublicMsg(), other)
+}
+
+// Matches reports whether got looks like want, ignoring their times and
+// stacks. The codes, public messages and user error flags must be equal,
+// and every info key of want must be in got, with a deeply equal value.
+// Extra info keys in got are ignored. This is useful in table-driven tests:
+//
+//	want := errs.UserError(errs.Info{"Field": "email"}, "Email is taken")
+//	if !errs.Matches(err, want) { t.Fatal(err) }
+func Matches(got, want Err) bool {
+	if got == nil || want == nil {
+		return got == nil && want == nil
+	}
+	if got.Code() != want.Code() || got.PublicMsg() != want.PublicMsg() || got.IsUserError() != want.IsUserError() {
+		return false
+	}
+	gotInfo := got.AllInfo()
+	for key, wantVal := range want.AllInfo() {
+		gotVal, hasKey := gotInfo[key]
+		if !hasKey || !reflect.DeepEqual(gotVal, wantVal) {
+			return false
+		}
+	}
+	return true
+}
+
+// Internal
+///////////
+
+// Check if the error with the given public message is equal to other
+func (e *err) equal(publicMsg string, other Err) bool {
+	if other == nil {
+		return false
+	}
+	return e.Code() == other.Code() &&
+		publicMsg == other.PublicMsg() &&
+		e.IsUserError() == other.IsUserError() &&
+		e.Fingerprint() == other.Fingerprint()
+}
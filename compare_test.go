@@ -0,0 +1,39 @@
+package errs_test
+
+import (
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestMatches(t *testing.T) {
+	got := errs.WrapWithCode("CODE", errs.UserError(errs.Info{"Field": "email", "Values": []int{1, 2}, "Extra": 1}, "Email is taken"), nil)
+	want := errs.WrapWithCode("CODE", errs.UserError(errs.Info{"Field": "email", "Values": []int{1, 2}}, "Email is taken"), nil)
+	assert(t, errs.Matches(got, want), "Expected matching errors")
+	assert(t, errs.Matches(nil, nil))
+
+	mismatches := map[string]errs.Err{
+		"code":      errs.WrapWithCode("OTHER", errs.UserError(errs.Info{"Field": "email"}, "Email is taken"), nil),
+		"message":   errs.WrapWithCode("CODE", errs.UserError(errs.Info{"Field": "email"}, "Email is invalid"), nil),
+		"userError": errs.NewWithCode("CODE", errs.Info{"Field": "email"}, "Email is taken"),
+		"infoValue": errs.WrapWithCode("CODE", errs.UserError(errs.Info{"Field": "name"}, "Email is taken"), nil),
+		"infoKey":   errs.WrapWithCode("CODE", errs.UserError(errs.Info{"Missing": 1}, "Email is taken"), nil),
+		"nil":       nil,
+	}
+	for name, want := range mismatches {
+		assert(t, !errs.Matches(got, want), "Expected mismatch", name)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	var created []errs.Err
+	for i := 0; i < 2; i++ {
+		created = append(created, errs.NewWithCode("CODE", errs.Info{"Attempt": i}, "publicMsg"))
+	}
+	assert(t, created[0].Equal(created[1]), "Expected same-site errors with different info to be equal")
+	assert(t, !created[0].Equal(errs.NewWithCode("CODE", nil, "publicMsg")), "Expected errors from different sites to differ")
+	other := created[1].Clone()
+	errs.Wrap(other, nil, "Outer")
+	assert(t, !created[0].Equal(other), "Expected errors with different messages to differ")
+	assert(t, !created[0].Equal(nil))
+}
@@ -0,0 +1,80 @@
+package errs
+
+import (
+	"context"
+	"errors"
+)
+
+// ContextWithInfo returns a copy of ctx which carries the given info,
+// merged over any info already carried by ctx. Errors created with
+// errs.NewCtx pick up the context's info automatically, which saves
+// passing request-scoped keys to every constructor, e.g
+//
+//	ctx = errs.ContextWithInfo(ctx, errs.Info{errs.KeyTraceID: traceID})
+func ContextWithInfo(ctx context.Context, info Info) context.Context {
+	merged := copyInfo(contextInfo(ctx))
+	if merged == nil {
+		merged = make(Info, len(info))
+	}
+	for key, val := range info {
+		merged[key] = val
+	}
+	return context.WithValue(ctx, infoCtxKey{}, merged)
+}
+
+// NewCtx creates a new Err like New, with the info given to
+// errs.ContextWithInfo for ctx merged into it. Keys in the given
+// info take precedence over the context's info.
+func NewCtx(ctx context.Context, info Info, publicMsg ...interface{}) Err {
+	return newErr(callers(0), nil, false, withContextInfo(ctx, info), publicMsg)
+}
+
+// FromContext wraps ctx.Err() like NewCtx, or returns nil if ctx is not done.
+// The error is retryable if the context's deadline was exceeded, and any
+// cause given to context.WithCancelCause et al is set as the "cause" info, e.g
+//
+//	case <-ctx.Done():
+//		return errs.FromContext(ctx, nil, "Request timed out")
+func FromContext(ctx context.Context, info Info, publicMsg ...interface{}) Err {
+	ctxErr := ctx.Err()
+	if ctxErr == nil {
+		return nil
+	}
+	info = withContextInfo(ctx, info)
+	if cause := context.Cause(ctx); cause != nil && cause != ctxErr {
+		info = copyInfo(info)
+		if info == nil {
+			info = Info{}
+		}
+		info["cause"] = cause
+	}
+	if errors.Is(ctxErr, context.DeadlineExceeded) {
+		return newErr(callers(0), ctxErr, false, info, publicMsg, withRetryable(true))
+	}
+	return newErr(callers(0), ctxErr, false, info, publicMsg)
+}
+
+// Internal
+///////////
+
+// infoCtxKey is the context key of the info given to ContextWithInfo
+type infoCtxKey struct{}
+
+// Get the info carried by ctx, or nil if there is none
+func contextInfo(ctx context.Context) Info {
+	info, _ := ctx.Value(infoCtxKey{}).(Info)
+	return info
+}
+
+// Get a copy of the context's info with the given info merged over it
+func withContextInfo(ctx context.Context, info Info) Info {
+	ctxInfo := contextInfo(ctx)
+	if ctxInfo == nil {
+		return info
+	}
+	merged := copyInfo(ctxInfo)
+	for key, val := range info {
+		merged[key] = val
+	}
+	return merged
+}
@@ -0,0 +1,51 @@
+package errs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestNewCtx(t *testing.T) {
+	ctx := errs.ContextWithInfo(context.Background(), errs.Info{errs.KeyTraceID: "trace-1", "Foo": "ctx"})
+	ctx = errs.ContextWithInfo(ctx, errs.Info{errs.KeyUserID: 7})
+	err := errs.NewCtx(ctx, errs.Info{"Foo": "Bar"}, "publicMsg")
+	assert(t, err.Info(errs.KeyTraceID) == "trace-1", err.Info(errs.KeyTraceID))
+	assert(t, err.Info(errs.KeyUserID) == 7, err.Info(errs.KeyUserID))
+	assert(t, err.Info("Foo") == "Bar", "Expected given info to take precedence", err.Info("Foo"))
+	assert(t, err.PublicMsg() == "publicMsg")
+
+	err = errs.NewCtx(ctx, nil)
+	err.WithInfo("Cat", "Mat")
+	assert(t, !errs.NewCtx(ctx, nil).HasInfo("Cat"), "Expected context info not to be shared between errors")
+
+	err = errs.NewCtx(context.Background(), errs.Info{"Foo": "Bar"})
+	assert(t, err.Info("Foo") == "Bar" && !err.HasInfo(errs.KeyTraceID))
+}
+
+func TestFromContext(t *testing.T) {
+	assert(t, errs.FromContext(context.Background(), nil) == nil, "Expected nil for a live context")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := errs.FromContext(ctx, errs.Info{"Foo": "Bar"}, "Cancelled")
+	assert(t, errors.Is(err, context.Canceled), "Expected the context error to be wrapped")
+	assert(t, !err.Retryable(), "Expected a cancelled context not to be retryable")
+	assert(t, !err.HasInfo("cause") && err.Info("Foo") == "Bar" && err.PublicMsg() == "Cancelled")
+
+	ctx, cancel = context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	err = errs.FromContext(ctx, nil)
+	assert(t, errors.Is(err, context.DeadlineExceeded), "Expected the deadline error to be wrapped")
+	assert(t, err.Retryable(), "Expected an exceeded deadline to be retryable")
+
+	cause := errors.New("shutting down")
+	ctx, cancelCause := context.WithCancelCause(errs.ContextWithInfo(context.Background(), errs.Info{errs.KeyTraceID: "trace-1"}))
+	cancelCause(cause)
+	err = errs.FromContext(ctx, nil)
+	assert(t, err.Info("cause") == cause, "Expected the cause info", err.Info("cause"))
+	assert(t, err.Info(errs.KeyTraceID) == "trace-1", "Expected the context info")
+}
@@ -1,37 +1,45 @@
 // Package errs improves on the standard `error` by encapsulating stack traces, timestamps,
 // optional internal information, and optional public user-facing messages.
 //
-// Usage
+// # Usage
 //
 // Create an empty error with stack trace and timestamp:
 //
-//  err := errs.New(nil)
-//  err.Time() // time.Time at time of creation
-//  err.Stack() // output from debug.Stack() at time of creation
+//	err := errs.New(nil)
+//	err.Time() // time.Time at time of creation
+//	err.Stack() // output from debug.Stack() at time of creation
 //
 // Create an error with associated internal info and a user-facing message:
 //
-//  userEmail := "user@example.com"
-//  emailExists := checkIfEmailExists(userEmail)
-//  if emailExists {
-//    err := errs.New(errs.Info{ "Email":userEmail }, userEmail, "is already taken. Try another!")
-//    return err
-//  }
-//  ...
-//  err.Info("Email") // "user@example.com"
-//  err.PublicMsg() // "user@example.com is already taken. Try another!"
+//	userEmail := "user@example.com"
+//	emailExists := checkIfEmailExists(userEmail)
+//	if emailExists {
+//	  err := errs.New(errs.Info{ "Email":userEmail }, userEmail, "is already taken. Try another!")
+//	  return err
+//	}
+//	...
+//	err.Info("Email") // "user@example.com"
+//	err.PublicMsg() // "user@example.com is already taken. Try another!"
 //
 // Wrap a standard error:
 //
-//  err := errors.New("An error")
-//  err = errs.Wrap(err, errs.Info{"Foo": "Bar"}, "User message")
-//  ...
-//  err.WrappedErr().Error() == "An error"
+//	err := errors.New("An error")
+//	err = errs.Wrap(err, errs.Info{"Foo": "Bar"}, "User message")
+//	...
+//	err.WrappedErr().Error() == "An error"
 package errs
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"runtime/debug"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -41,80 +49,507 @@ type Err interface {
 	// (errs.Err implements the error interface).
 	Error() string
 
-	// Stack returns the result of debug.Stack() from the time when this Err was created.
+	// Stack returns a textual stack trace, similar to the output of debug.Stack(),
+	// from the time when this Err was created.
 	Stack() []byte
 
+	// StackFrames returns the resolved stack frames from the time when this Err was created.
+	StackFrames() []Frame
+
+	// StackTrace returns the stack frames as a StackTrace, which formats with
+	// the same verbs as github.com/pkg/errors' StackTrace, e.g %+v. It is not
+	// of that type, so reporters which look for `StackTrace() errors.StackTrace`
+	// do not recognize it.
+	StackTrace() StackTrace
+
+	// StackLinks returns the stack frames as "path/to/file.go:123" links,
+	// which editors and terminals can open. See SetSourceLinkPrefix for
+	// linking to e.g GitHub instead.
+	StackLinks() []string
+
+	// AppFrames returns the stack frames whose function or file path
+	// contains modulePrefix, leaving out e.g runtime and stdlib frames,
+	// e.g `err.AppFrames("github.com/foo/bar")`. An empty modulePrefix
+	// means the main module path from the build info, if known.
+	AppFrames(modulePrefix string) []Frame
+
+	// AllStacks returns the stacks of all goroutines from the time when this
+	// Err was created, in the format of runtime.Stack, if it was created with
+	// errs.NewAllStacks. Otherwise it returns nil.
+	AllStacks() []byte
+
+	// Location returns the file name and line where this Err was created,
+	// e.g "user.go:123", or an empty string if it has no stack. It only
+	// resolves a single frame, so it is cheap enough for every log line.
+	// With errs.SetPreserveChain, each wrapped layer has its own Location.
+	Location() string
+
+	// Callers returns the raw program counters of the stack from the time
+	// when this Err was created, as returned by runtime.Callers. This is
+	// useful for symbolizing against a specific build's debug info.
+	Callers() []uintptr
+
+	// Fingerprint returns a stable hash of the error's code and the top
+	// application frames of its stack, for grouping identical errors.
+	// Variable data like the time and info are deliberately excluded, so
+	// errors created on the same line with the same code have equal fingerprints.
+	Fingerprint() string
+
+	// Equal reports whether the error is a duplicate of other, i.e if their
+	// codes, public messages, user error flags and fingerprints are equal.
+	// Volatile fields like the time, goroutine and info values are ignored,
+	// e.g for deduplicating errors before reporting them. See also errs.Matches
+	Equal(other Err) bool
+
 	// Time returns the time.Time at which this Err was created.
 	Time() time.Time
 
+	// GoroutineID returns the ID of the goroutine which created this Err, if
+	// errs.SetCaptureGoroutineID(true) was set at the time. Otherwise it returns 0.
+	GoroutineID() uint64
+
+	// TimeUTC returns Time() in UTC, with its monotonic clock reading stripped.
+	// This is stable across serialization and comparable across machines.
+	TimeUTC() time.Time
+
 	// If errs.Wrap was used then WrappedError returns the wrapped error.
 	WrappedError() error
 
+	// Depth returns the number of errs.New and errs.Wrap layers of the error,
+	// including layers which were merged by Wrap. A newly created Err has
+	// depth 1, and each errs.Wrap of it adds 1, e.g for wrapping metrics.
+	Depth() int
+
+	// WrappedErrors returns every error in the chain of wrapped errors,
+	// from the outermost wrapped error to the root cause. The chain is
+	// followed through both errs.Errs and standard errors that implement
+	// `Unwrap() error`.
+	WrappedErrors() []error
+
+	// Cause returns the deepest wrapped error which is not itself an errs.Err,
+	// or nil if there is none. This makes errs.Err compatible with tools
+	// that understand github.com/pkg/errors' `Cause() error`.
+	Cause() error
+
 	// If errs.Wrap or errs.New was called with any publicMsg values
 	// then PublicMsg returns a string representation of those values.
 	// This is useful for bubbling up user-facing message strings,
 	// e.g `errs.New(nil, userEmail, "is already taken. Try another!")`
 	PublicMsg() string
 
+	// PublicMsgParts returns the separate public messages which PublicMsg
+	// joins, ordered from the outermost errs.Wrap to the innermost errs.New.
+	// This is useful for e.g translating each message separately.
+	PublicMsgParts() []string
+
 	// If errs.Wrap or errs.New was called with an errs.Info object
 	// then Info("Foo") return the value of errs.Info{"Foo":...}
 	// This is useful for bubbling up internal-facing info,
 	// e.g `errs.Wrap(sqlError, { "SqlString":sqlStr, "SqlArgs":sqlArgs })`
 	Info(name string) interface{}
 
-	// AllInfo returns all info key-value-pairs passed through errs.New or errs.Wrap
+	// HasInfo reports whether the info has the given key, even if its value is nil.
+	HasInfo(name string) bool
+
+	// InfoString returns Info(name) if it is a string. The bool reports
+	// whether the key existed and was a string.
+	InfoString(name string) (string, bool)
+
+	// InfoInt returns Info(name) converted to an int64 if it is a number.
+	// The bool reports whether the key existed and was a number.
+	InfoInt(name string) (int64, bool)
+
+	// InfoBool returns Info(name) if it is a bool. The bool reports
+	// whether the key existed and was a bool.
+	InfoBool(name string) (bool, bool)
+
+	// InfoKeys returns the sorted keys of the info, or an empty slice if there is none.
+	// This is cheaper than AllInfo when only the keys are needed.
+	InfoKeys() []string
+
+	// InfoAll returns all values merged for the given key, in the order they
+	// were set, e.g one "Query" value per Wrap. It relies on the "_duplicate"
+	// key suffixes of MergeSuffix, so with other merge policies only the
+	// current value is returned.
+	InfoAll(key string) []interface{}
+
+	// AllInfo returns a copy of all info key-value-pairs passed through errs.New or errs.Wrap.
+	// Modifying the returned Info does not affect the error.
 	AllInfo() Info
 
+	// LogInfo returns a copy of the info as it is rendered in logs, for logging
+	// and telemetry adapters: pairs dropped by the SetInfoLogFilter filter are
+	// left out, and values are redacted and truncated according to Redactor
+	// and SetMaxInfoValueLen.
+	LogInfo() Info
+
+	// AllFields returns a flat map of the info and the reserved keys
+	// "publicMsg", "code", "userError", "time" and "location", for logging
+	// adapters. Info keys which collide with a reserved key (or "info")
+	// are moved into a nested Info under the "info" key. Info values are
+	// rendered as for LogString, e.g with redaction.
+	AllFields() map[string]interface{}
+
+	// WithInfo merges the given key-value-pair into the error's info, like
+	// errs.Wrap would but without wrapping, and returns the error.
+	WithInfo(key string, val interface{}) Err
+
+	// WithInfoMap merges the given info into the error's info, like
+	// errs.Wrap would but without wrapping, and returns the error.
+	WithInfoMap(info Info) Err
+
+	// Clone returns a copy of the error with its own copy of the info map, so
+	// that e.g WithInfo on the clone does not affect the original. The info
+	// values themselves, the stack and the time are shared with the original.
+	// This is useful for adding request-specific info to package-level errors.
+	Clone() Err
+
+	// Sanitize returns a new error with only the public message, code,
+	// HTTP status and user error flag of the error. Its info, stack and
+	// wrapped error are empty, which makes it safe to serialize to
+	// untrusted clients.
+	Sanitize() Err
+
+	// WithRequestID sets the errs.KeyRequestID info, like WithInfo, and returns the error.
+	WithRequestID(id string) Err
+
+	// WithTraceID sets the errs.KeyTraceID info, like WithInfo, and returns the error.
+	WithTraceID(id string) Err
+
 	// LogString returns a string suitable for logging
 	LogString() string
 
+	// LogStringCompact returns LogString on a single line, for line-based log
+	// ingestion. The stack frames are separated by " <- ", and newlines
+	// within e.g info values and public messages are escaped as `\n`.
+	LogStringCompact() string
+
+	// PublicJSON returns a JSON object which is safe to send to API clients,
+	// with only the public message, the code, and the HTTP status if one was given,
+	// e.g `{"message":"Wrong username/password","code":"BAD_LOGIN","status":401}`.
+	// Info, stack and wrapped errors are deliberately omitted. Use
+	// json.Marshal for the full internal representation for logs.
+	PublicJSON() ([]byte, error)
+
+	// ProblemJSON returns an RFC 7807 `application/problem+json` object, with
+	// the status from HTTPStatus, the detail from PublicMsg, and the title from
+	// Code or else the status text. Info keys given to SetProblemInfoKeys are
+	// included as extension members, e.g
+	// `{"type":"about:blank","title":"BAD_LOGIN","status":401,"detail":"Wrong username/password"}`
+	ProblemJSON() ([]byte, error)
+
 	// IsUserError returns false if it was created with errs.UserError.
 	// Useful for e.g escaping out of a call stack but not logging it as
 	// an unexpected/critical error,
 	// e.g `errs.UserError(nil, "Wrong username/password")`
 	IsUserError() bool
+
+	// LocalizationKey returns the localization key given to errs.Localized,
+	// or an empty string if there is none.
+	LocalizationKey() string
+
+	// LocalizationArgs returns a copy of the localization args given to
+	// errs.Localized, for translating LocalizationKey. It returns nil
+	// if there are none.
+	LocalizationArgs() map[string]interface{}
+
+	// Code returns the machine-readable error code given to errs.NewWithCode
+	// or errs.WrapWithCode, or an empty string if there is none.
+	// e.g `errs.NewWithCode("USER_EMAIL_TAKEN", nil).Code() == "USER_EMAIL_TAKEN"`
+	Code() string
+
+	// HTTPStatus returns the HTTP status code given to errs.HTTPError.
+	// If none was given then it defaults to 400 for user errors and 500 otherwise,
+	// e.g `w.WriteHeader(err.HTTPStatus())`
+	HTTPStatus() int
+
+	// WithStatus sets the HTTP status of the error, overriding any previous
+	// status, and returns the error, e.g `errs.Wrap(dbErr, nil).WithStatus(503)`
+	WithStatus(status int) Err
+
+	// Retryable returns true if the error was created with errs.Retryable,
+	// or marked with WithRetryable(true) at any layer of wrapping. Useful
+	// for distinguishing transient errors from permanent ones,
+	// e.g `if e, ok := errs.IsErr(err); ok && e.Retryable() { ... }`
+	Retryable() bool
+
+	// WithRetryable marks the error as retryable or not, overriding
+	// any previous value, and returns the error.
+	WithRetryable(retryable bool) Err
+
+	// Level returns the severity of the error, as given to errs.NewLevel or
+	// WithLevel. It defaults to LevelWarn for user errors and LevelError otherwise.
+	Level() Level
+
+	// WithLevel sets the severity of the error, and returns the error.
+	WithLevel(level Level) Err
 }
 
 // New creates a new Err with the given Info and optional public message
 func New(info Info, publicMsg ...interface{}) Err {
-	return newErr(debug.Stack(), nil, false, info, publicMsg)
+	return newErr(callers(0), nil, false, info, publicMsg)
+}
+
+// NewIf creates a new Err like New if cond is true, and returns nil otherwise,
+// e.g `return errs.NewIf(len(name) == 0, nil, "Please enter a name")`
+func NewIf(cond bool, info Info, publicMsg ...interface{}) Err {
+	if !cond {
+		return nil
+	}
+	return newErr(callers(0), nil, false, info, publicMsg)
+}
+
+// NewSkip creates a new Err like New, but removes skip additional frames
+// from the top of the captured stack. This is useful for helper functions
+// which create errors, so that the stack starts at the helper's caller:
+//
+//	func fail(msg string) errs.Err { return errs.NewSkip(1, nil, msg) }
+func NewSkip(skip int, info Info, publicMsg ...interface{}) Err {
+	return newErr(callers(skip), nil, false, info, publicMsg)
+}
+
+// NewNoStack creates a new Err like New, but without capturing a stack.
+// Its Stack() returns nil. This avoids the cost of capturing stacks for
+// expected control-flow errors.
+func NewNoStack(info Info, publicMsg ...interface{}) Err {
+	return newErr(nil, nil, false, info, publicMsg)
+}
+
+// NewAllStacks creates a new Err like New, and also captures the stacks of
+// all running goroutines, which is useful for diagnosing deadlocks and
+// goroutine leaks. See Err.AllStacks. Capturing all stacks stops the world
+// and can produce a lot of output, so only use it for rare, serious errors.
+func NewAllStacks(info Info, publicMsg ...interface{}) Err {
+	return newErr(callers(0), nil, false, info, publicMsg, withAllStacks(allStacks()))
+}
+
+// NewWithCode creates a new Err with the given error code, Info and optional public message.
+// See Err.Code
+func NewWithCode(code string, info Info, publicMsg ...interface{}) Err {
+	return newErr(callers(0), nil, false, info, publicMsg, withCode(code))
 }
 
 // Wrap the given error in an errs.Err. If err is nil, Wrap returns nil.
 // Use Err.WrappedError for direct access to the wrapped error.
 func Wrap(wrapErr error, info Info, publicMsg ...interface{}) Err {
+	return wrap(0, wrapErr, "", info, publicMsg)
+}
+
+// WrapIf wraps the given error like Wrap if it is non-nil, and returns nil
+// otherwise. This replaces the common `if err != nil` guard with a single
+// return, e.g `return errs.WrapIf(json.Unmarshal(data, &user), nil, "Invalid user")`.
+// Since the nil is returned as an untyped nil interface, the returned error
+// compares equal to nil.
+func WrapIf(wrapErr error, info Info, publicMsg ...interface{}) Err {
 	if wrapErr == nil {
 		return nil
 	}
-	if info == nil {
-		info = Info{}
-	}
-	if errsErr, isErr := IsErr(wrapErr); isErr {
-		if errStructErr, isErrsErr := errsErr.(*err); isErrsErr {
-			errStructErr.mergeIn(info, publicMsg)
-			return errStructErr
-		}
-		return errsErr
-	}
-	return newErr(debug.Stack(), wrapErr, false, info, publicMsg)
+	return wrap(0, wrapErr, "", info, publicMsg)
+}
+
+// WrapSkip wraps the given error like Wrap, but removes skip additional
+// frames from the top of a newly captured stack, like NewSkip. Note that a
+// stack is only captured if wrapErr is not already an errs.Err, or if
+// SetPreserveChain(true) is set. Otherwise Wrap merges into wrapErr, which
+// keeps its original stack, and skip has no effect.
+//
+//	func query(sql string) error { return errs.WrapSkip(1, db.Exec(sql), errs.Info{"SQL": sql}) }
+func WrapSkip(skip int, wrapErr error, info Info, publicMsg ...interface{}) Err {
+	return wrap(skip, wrapErr, "", info, publicMsg)
+}
+
+// Wrapf wraps the given error like Wrap, with a public message formatted
+// with fmt.Sprintf. Arguments which are errs.Errs are formatted with their
+// PublicMsg. The info may be nil. If err is nil, Wrapf returns nil,
+// e.g `errs.Wrapf(err, nil, "Failed to open %s", path)`
+func Wrapf(wrapErr error, info Info, format string, argv ...interface{}) Err {
+	return wrap(0, wrapErr, "", info, []interface{}{fmt.Sprintf(format, publicArgs(argv)...)})
+}
+
+// WrapWithCode wraps the given error like Wrap, and sets its error code.
+// If wrapErr already has a code then it is replaced by the given code.
+// See Err.Code
+func WrapWithCode(code string, wrapErr error, info Info, publicMsg ...interface{}) Err {
+	return wrap(0, wrapErr, code, info, publicMsg)
 }
 
 // UserError creates an errs.Err which returns true for IsUserError().
-// See Err.IsUserError
+// User errors are expected, so they do not capture a stack by default,
+// and their Stack() returns nil. See SetUserErrorStack and Err.IsUserError
 func UserError(info Info, publicMsg ...interface{}) Err {
-	return newErr(debug.Stack(), nil, true, info, publicMsg)
+	return newErr(userErrorCallers(), nil, true, info, publicMsg)
 }
 
-// Format creates and wraps an error with the given error string. Equivalent to:
-// `errs.Wrap(fmt.Errorf(format, args...))`
+// UserErrorWrap wraps the given error like Wrap, in an errs.Err which returns
+// true for IsUserError(). Like UserError, it does not capture a stack by
+// default, see SetUserErrorStack. If err is nil, UserErrorWrap returns nil, e.g
+// `errs.UserErrorWrap(parseErr, nil, "Invalid date. Use YYYY-MM-DD")`
+func UserErrorWrap(wrapErr error, info Info, publicMsg ...interface{}) Err {
+	if wrapErr == nil {
+		return nil
+	}
+	if _, hasBase := wrapErr.(baser); hasBase {
+		// Add a layer rather than merging, since isUserErr is immutable
+		return newErr(userErrorCallers(), wrapErr, true, info, publicMsg, withChained())
+	}
+	return newErr(userErrorCallers(), wrapErr, true, info, publicMsg)
+}
+
+// UserErrorNoStack creates a new Err like UserError, but without capturing a stack.
+// See NewNoStack
+func UserErrorNoStack(info Info, publicMsg ...interface{}) Err {
+	return newErr(nil, nil, true, info, publicMsg)
+}
+
+// HTTPError creates an errs.Err with the given HTTP status code.
+// See Err.HTTPStatus
+func HTTPError(status int, info Info, publicMsg ...interface{}) Err {
+	return newErr(callers(0), nil, false, info, publicMsg, withHTTPStatus(status))
+}
+
+// Retryable creates an errs.Err which returns true for Retryable().
+// See Err.Retryable
+func Retryable(info Info, publicMsg ...interface{}) Err {
+	return newErr(callers(0), nil, false, info, publicMsg, withRetryable(true))
+}
+
+// Format creates and wraps an error with the given error string, and uses
+// the same string as the public message. Equivalent to:
+// `errs.Wrap(fmt.Errorf(format, args...), info, fmt.Sprintf(format, args...))`,
+// except that arguments which are errs.Errs are formatted with their
+// PublicMsg in the public message, so that their internal details do not
+// leak into it.
 func Format(info Info, format string, argv ...interface{}) Err {
-	return newErr(debug.Stack(), fmt.Errorf(format, argv...), false, info, nil)
+	stdErr := fmt.Errorf(format, argv...)
+	publicMsg := fmt.Errorf(format, publicArgs(argv)...).Error()
+	return newErr(callers(0), stdErr, false, info, []interface{}{publicMsg})
+}
+
+// Errorf creates an error like fmt.Errorf, and uses the formatted string as
+// the public message. An error given with the %w verb becomes the wrapped
+// error, and is returned by Unwrap. With multiple %w verbs the wrapped error
+// unwraps to all of them, like with fmt.Errorf. Arguments which are errs.Errs
+// are formatted with their PublicMsg, so that their internal details do not
+// leak into the public message, e.g `errs.Errorf(nil, "reading %s: %w", path, err)`
+func Errorf(info Info, format string, argv ...interface{}) Err {
+	stdErr := fmt.Errorf(format, argv...)
+	var wrappedErr error
+	switch unwrapper := stdErr.(type) {
+	case interface{ Unwrap() error }:
+		wrappedErr = unwrapper.Unwrap()
+	case interface{ Unwrap() []error }:
+		wrappedErr = stdErr
+	}
+	publicMsg := fmt.Errorf(format, publicArgs(argv)...).Error()
+	return newErr(callers(0), wrappedErr, false, info, []interface{}{publicMsg})
+}
+
+// PublicMsgf creates a new Err with a public message formatted with
+// fmt.Sprintf. Unlike the variadic publicMsg of e.g errs.New, which always
+// separates its parts with spaces, this gives precise control over spacing.
+// Arguments which are errs.Errs are formatted with their PublicMsg. Info can
+// be added with Err.WithInfo:
+//
+//	errs.New(nil, "Value", ":", 5).PublicMsg() // "Value : 5"
+//	errs.PublicMsgf("Value:%d", 5).PublicMsg() // "Value:5"
+func PublicMsgf(format string, argv ...interface{}) Err {
+	return newErr(callers(0), nil, false, Info{}, []interface{}{fmt.Sprintf(format, publicArgs(argv)...)})
+}
+
+// SetPreserveChain controls how Wrap treats errors which are already errs.Errs.
+// By default, Wrap merges the new info and public message into the wrapped
+// errs.Err and returns it, so only the innermost stack and time survive.
+// With SetPreserveChain(true), Wrap instead creates a new errs.Err layer
+// which references the wrapped one, so that each layer keeps its own stack
+// and time. Accessors like AllInfo and PublicMsg then aggregate across all
+// the layers as if they had been merged.
+func SetPreserveChain(preserve bool) {
+	preserveChain.Store(preserve)
+}
+
+// SetAutoClassify controls whether Wrap marks well-known transient errors
+// as retryable, so that Err.Retryable returns true for them without calling
+// WithRetryable. The transient errors are context.DeadlineExceeded,
+// io.ErrUnexpectedEOF, and net.Errors whose Temporary() is true, also
+// when they are wrapped by other errors. The default is false.
+func SetAutoClassify(classify bool) {
+	autoClassify.Store(classify)
+}
+
+// MergePolicy determines how info keys which collide are merged,
+// e.g when Wrap merges info into an errs.Err which already has the key.
+type MergePolicy int
+
+// Merge policies for SetMergePolicy
+const (
+	// MergeSuffix keeps all values, by renaming new keys which collide with
+	// an existing key with a "_duplicate" suffix. This is the default.
+	MergeSuffix MergePolicy = iota
+	// MergeOverwrite keeps the newest value
+	MergeOverwrite
+	// MergeKeepFirst keeps the original value
+	MergeKeepFirst
+)
+
+// MergeReplacePublic is a flag which can be combined with any of the merge
+// policies, to make Wrap replace the public message of the wrapped errs.Err
+// with the new one, rather than prepending the new one with the delimiter. Wraps
+// without a public message keep the existing message,
+// e.g `errs.SetMergePolicy(errs.MergeSuffix | errs.MergeReplacePublic)`
+const MergeReplacePublic MergePolicy = 1 << 8
+
+// SetMergePolicy sets how colliding info keys and public messages
+// are merged. See MergePolicy and MergeReplacePublic
+func SetMergePolicy(policy MergePolicy) {
+	mergePolicy.Store(int64(policy))
+}
+
+// SetPublicMsgDelimiter sets the delimiter between the public messages of
+// wrapped errors in PublicMsg, e.g "; " or "\n". The default is " - ".
+func SetPublicMsgDelimiter(delimiter string) {
+	publicMsgDelimiter.Store(&delimiter)
+}
+
+// SetMaxWrapDepth sets the maximum Depth up to which Wrap merges info and
+// public messages into a wrapped errs.Err. Beyond it, Wrap returns the error
+// without adding more info or public messages, which keeps e.g retry loops
+// from growing the error without bounds. A depth of 0 (the default) means
+// no limit.
+func SetMaxWrapDepth(depth int) {
+	maxWrapDepth.Store(int64(depth))
+}
+
+// SetUserErrorStack controls whether user errors created by errs.UserError,
+// errs.UserErrorWrap and errs.Validation capture a stack, e.g
+// for debugging where user errors originate. The default is false.
+func SetUserErrorStack(capture bool) {
+	userErrorStack.Store(capture)
+}
+
+// SetClock sets the function used to get the creation time of errors.
+// This is useful for freezing time in tests. Passing nil restores
+// the default, time.Now.
+func SetClock(clock func() time.Time) {
+	if clock == nil {
+		clock = time.Now
+	}
+	errsClock.Store(&clock)
 }
 
 // Info allows for associating key-value-pair info with an error for debugging,
 // e.g `errs.Wrap(sqlError, { "SqlString":sqlStr, "SqlArgs":sqlArgs })`
 type Info map[string]interface{}
 
+// Infof returns an Info with the single given key, whose value is formatted
+// with fmt.Sprintf. Constructors like New and Wrap merge any additional Info
+// arguments which are given instead of public message parts into the info,
+// so Infof can be used for several keys at once, e.g
+//
+//	errs.New(errs.Infof("Query", "%s %v", query, args), errs.Infof("Took", "%dms", ms))
+func Infof(key, format string, args ...interface{}) Info {
+	return Info{key: fmt.Sprintf(format, args...)}
+}
+
 // IsErr checks if err is an errs.Err, and return it as an errs.Err if it is.
 // This is equivalent to err.(errs.Err)
 func IsErr(err error) (Err, bool) {
@@ -122,69 +557,597 @@ func IsErr(err error) (Err, bool) {
 	return errsErr, isErr
 }
 
+// Sentinels for matching errs.Errs by their flags with errors.Is, rather than
+// by their values, e.g `if errors.Is(err, errs.ErrUser) { ... }`
+var (
+	// ErrUser matches any errs.Err for which IsUserError is true
+	ErrUser = errors.New("errs: user error")
+	// ErrRetryable matches any errs.Err for which Retryable is true
+	ErrRetryable = errors.New("errs: retryable error")
+)
+
 // Internal
 ///////////
 
-// err implements Err
+// err implements Err. mu guards the fields that are mutated after
+// creation, e.g when an err is merged into by Wrap: info, publicMsg,
+// code, httpStatus, retryable and level.
 type err struct {
-	stack      []byte
-	time       time.Time
-	wrappedErr error
-	isUserErr  bool
-	info       Info
-	publicMsg  string
+	mu          sync.RWMutex
+	pcs         []uintptr
+	stackOnce   sync.Once
+	stack       []byte
+	time        time.Time
+	goroutineID uint64 // 0 if not captured
+	wrappedErr  error
+	isUserErr   bool
+	info        Info
+	publicMsg   []string // Parts of the public message, outermost first
+	code        string
+	httpStatus  int
+	retryable   bool
+	// retryableSet is true if retryable was explicitly set,
+	// such that it overrides the retryable flag of inner layers.
+	retryableSet bool
+	level        Level // 0 if not set
+	numMerged    int   // The number of Wraps merged into this err
+	allStacks    []byte
+	// The key and args given to errs.Localized, which are
+	// never modified after the error is created
+	localizationKey  string
+	localizationArgs map[string]interface{}
+	// chained is true if wrappedErr is an inner layer of this error,
+	// created by Wrap with SetPreserveChain(true).
+	chained bool
+}
+
+var (
+	preserveChain atomic.Bool
+	mergePolicy   atomic.Int64
+	autoClassify  atomic.Bool
+	maxWrapDepth  atomic.Int64
+	// Whether UserError captures stacks, see SetUserErrorStack
+	userErrorStack atomic.Bool
+	// The delimiter set with SetPublicMsgDelimiter, or nil for " - "
+	publicMsgDelimiter atomic.Pointer[string]
+)
+
+// The clock set with SetClock
+var errsClock atomic.Pointer[func() time.Time]
+
+// Get the current time from the clock set with SetClock
+func now() time.Time {
+	if clock := errsClock.Load(); clock != nil {
+		return (*clock)()
+	}
+	return time.Now()
+}
+
+// baser is implemented by err, and by all types which embed it
+type baser interface {
+	base() *err
+}
+
+func (e *err) base() *err { return e }
+
+// errOption configures an err before it is passed to OnError hooks
+type errOption func(e *err)
+
+func withCode(code string) errOption      { return func(e *err) { e.code = code } }
+func withHTTPStatus(status int) errOption { return func(e *err) { e.httpStatus = status } }
+func withRetryable(retryable bool) errOption {
+	return func(e *err) { e.retryable, e.retryableSet = retryable, true }
+}
+func withChained() errOption { return func(e *err) { e.chained = true } }
+func withAllStacks(stacks []byte) errOption {
+	return func(e *err) { e.allStacks = stacks }
+}
+
+// Create a new err, and pass it to any OnError hooks
+func newErr(pcs []uintptr, wrappedErr error, isUserErr bool, info Info, publicMsgParts []interface{}, opts ...errOption) *err {
+	e := buildErr(pcs, wrappedErr, isUserErr, info, publicMsgParts)
+	for _, opt := range opts {
+		opt(e)
+	}
+	reportErr(e)
+	return e
+}
+
+// Create a new err, without passing it to OnError hooks
+func buildErr(pcs []uintptr, wrappedErr error, isUserErr bool, info Info, publicMsgParts []interface{}) *err {
+	info, publicMsgParts = splitInfoArgs(info, publicMsgParts)
+	publicMsg := appendPublicMsg(nil, publicMsgParts)
+	return &err{
+		pcs:         pcs,
+		time:        now(),
+		goroutineID: goroutineID(),
+		wrappedErr:  wrappedErr,
+		isUserErr:   isUserErr,
+		info:        info,
+		publicMsg:   publicMsg,
+	}
+}
+
+// Get the callers for a new user error, or nil unless SetUserErrorStack is true
+func userErrorCallers() []uintptr {
+	if !userErrorStack.Load() {
+		return nil
+	}
+	return callers(0)
+}
+
+// Wrap wrapErr, merging into it if it is already an err. The code is only set
+// if it is non-empty. Skip frames are removed from any newly captured stack.
+func wrap(skip int, wrapErr error, code string, info Info, publicMsg []interface{}) Err {
+	if wrapErr == nil {
+		return nil
+	}
+	if info == nil {
+		info = Info{}
+	}
+	if errsErr, isErr := IsErr(wrapErr); isErr {
+		baseErr, hasBase := errsErr.(baser)
+		if max := maxWrapDepth.Load(); max > 0 && int64(errsErr.Depth()) >= max {
+			if hasBase {
+				baseErr.base().mergeIn(code, nil, nil)
+				baseErr.base().addMerged()
+			}
+			return errsErr
+		}
+		if hasBase && preserveChain.Load() {
+			return newErr(callers(skip), wrapErr, false, info, publicMsg, withCode(code), withChained())
+		} else if hasBase {
+			baseErr.base().mergeIn(code, info, publicMsg)
+			baseErr.base().addMerged()
+		}
+		return errsErr
+	}
+	if autoClassify.Load() && isTransient(wrapErr) {
+		return newErr(callers(skip), wrapErr, false, info, publicMsg, withCode(code), withRetryable(true))
+	}
+	return newErr(callers(skip), wrapErr, false, info, publicMsg, withCode(code))
+}
+
+// publicMsgErr formats an Err with its PublicMsg, for formatting public messages
+type publicMsgErr struct {
+	err Err
+}
+
+func (p publicMsgErr) Error() string { return p.err.PublicMsg() }
+
+// Get a copy of the format arguments in which errs.Errs are replaced
+// by publicMsgErrs, for formatting public messages
+func publicArgs(argv []interface{}) []interface{} {
+	publicArgv := make([]interface{}, len(argv))
+	for i, arg := range argv {
+		if errsErr, isErr := arg.(Err); isErr {
+			arg = publicMsgErr{errsErr}
+		}
+		publicArgv[i] = arg
+	}
+	return publicArgv
+}
+
+// Check if err is a well-known transient error. See SetAutoClassify
+func isTransient(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Temporary()
+}
+
+// Implements Err
+func (e *err) Time() time.Time    { return e.time }
+func (e *err) TimeUTC() time.Time { return e.time.UTC().Round(0) }
+func (e *err) Error() string      { return e.LogString() }
+func (e *err) String() string     { return e.LogString() }
+
+// Implements Err
+func (e *err) WrappedError() error {
+	if e.chained {
+		return e.view().WrappedError()
+	}
+	return e.wrappedErr
+}
+
+// Implements Err
+func (e *err) IsUserError() bool {
+	if e.chained {
+		return e.view().IsUserError()
+	}
+	return e.isUserErr
+}
+
+// Implements Err
+func (e *err) PublicMsg() string {
+	if e.chained {
+		return e.view().PublicMsg()
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.publicMsgStr()
+}
+
+// Implements Err
+func (e *err) PublicMsgParts() []string {
+	if e.chained {
+		return e.view().PublicMsgParts()
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return append([]string{}, e.publicMsg...)
 }
 
-func newErr(stack []byte, wrappedErr error, isUserErr bool, info Info, publicMsgParts []interface{}) Err {
-	publicMsg := concatArgs(publicMsgParts...)
-	return &err{stack, time.Now(), wrappedErr, isUserErr, info, publicMsg}
+// Implements Err
+func (e *err) Code() string {
+	if e.chained {
+		return e.view().Code()
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.code
+}
+
+// Implements Err. AllInfo returns a copy of the info,
+// so that it is safe to read while the error is being wrapped.
+func (e *err) AllInfo() Info {
+	if e.chained {
+		return e.view().AllInfo()
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return copyInfo(e.info)
+}
+
+// Implements Err
+func (e *err) HTTPStatus() int {
+	if e.chained {
+		return e.view().HTTPStatus()
+	}
+	e.mu.RLock()
+	status := e.httpStatus
+	e.mu.RUnlock()
+	if status != 0 {
+		return status
+	}
+	if e.isUserErr {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// Implements Err
+func (e *err) Retryable() bool {
+	if e.chained {
+		return e.view().Retryable()
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.retryable
 }
 
 // Implements Err
-func (e *err) Stack() []byte       { return e.stack }
-func (e *err) Time() time.Time     { return e.time }
-func (e *err) WrappedError() error { return e.wrappedErr }
-func (e *err) PublicMsg() string   { return e.publicMsg }
-func (e *err) Error() string       { return e.LogString() }
-func (e *err) String() string      { return e.LogString() }
-func (e *err) AllInfo() Info       { return e.info }
-func (e *err) IsUserError() bool   { return e.isUserErr }
+func (e *err) WithStatus(status int) Err {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.httpStatus = status
+	return e
+}
+
+// Implements Err
+func (e *err) WithRetryable(retryable bool) Err {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.retryable, e.retryableSet = retryable, true
+	return e
+}
+
+// Unwrap returns the wrapped error, so that errors.Is and errors.As can
+// walk through an errs.Err. By default Wrap merges nested errs.Errs into the
+// innermost one, so the wrapped error is the original error passed to Wrap.
+// With SetPreserveChain(true), it is the next inner layer instead.
+func (e *err) Unwrap() error { return e.wrappedErr }
+
+// Implements Err
+func (e *err) Depth() int {
+	e.mu.RLock()
+	depth := 1 + e.numMerged
+	e.mu.RUnlock()
+	if e.chained {
+		depth += e.wrappedErr.(Err).Depth()
+	}
+	return depth
+}
+
+// Implements Err
+func (e *err) WrappedErrors() []error {
+	var chain []error
+	for wrappedErr := e.wrappedErr; wrappedErr != nil; wrappedErr = errors.Unwrap(wrappedErr) {
+		chain = append(chain, wrappedErr)
+	}
+	return chain
+}
+
+// Implements Err
+func (e *err) Cause() error {
+	cause := e.wrappedErr
+	for {
+		errsErr, isErr := IsErr(cause)
+		if !isErr {
+			return cause
+		}
+		cause = errsErr.WrappedError()
+	}
+}
+
+// Is supports errors.Is for the ErrUser and ErrRetryable sentinels
+func (e *err) Is(target error) bool {
+	switch target {
+	case ErrUser:
+		return e.IsUserError()
+	case ErrRetryable:
+		return e.Retryable()
+	}
+	return false
+}
+
+// Timeout implements net.Error. It delegates to the
+// wrapped error if it is a net.Error, and otherwise returns false.
+func (e *err) Timeout() bool {
+	var netErr net.Error
+	return errors.As(e.wrappedErr, &netErr) && netErr.Timeout()
+}
+
+// Temporary implements net.Error. It delegates to the
+// wrapped error if it is a net.Error, and otherwise returns false.
+func (e *err) Temporary() bool {
+	var netErr net.Error
+	return errors.As(e.wrappedErr, &netErr) && netErr.Temporary()
+}
 
 // Implements Err
 func (e *err) Info(key string) interface{} {
+	if e.chained {
+		return e.view().Info(key)
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	if e.info == nil {
 		return nil
 	}
-	return e.info[key]
+	return unwrapSecret(e.info[key])
+}
+
+// Implements Err
+func (e *err) WithInfo(key string, val interface{}) Err {
+	return e.WithInfoMap(Info{key: val})
+}
+
+// Implements Err
+func (e *err) WithInfoMap(info Info) Err {
+	e.mergeIn("", info, nil)
+	return e
+}
+
+// Implements Err
+func (e *err) Clone() Err {
+	return e.clone()
 }
 
 // Implements Err
+func (e *err) Sanitize() Err {
+	return e.sanitize(e.PublicMsgParts())
+}
+
+// Implements Err
+func (e *err) WithRequestID(id string) Err {
+	return e.WithInfo(KeyRequestID, id)
+}
+
+// Implements Err
+func (e *err) WithTraceID(id string) Err {
+	return e.WithInfo(KeyTraceID, id)
+}
+
+// Implements Err. The stack is omitted if there is none.
+// The format can be customized with SetLogTemplate.
 func (e *err) LogString() string {
-	return concatArgs("Error",
+	if tmpl := logTemplate.Load(); tmpl != nil {
+		return e.templateLogString(tmpl)
+	}
+	return e.defaultLogString()
+}
+
+// Get the LogString in the default format
+func (e *err) defaultLogString() string {
+	stack := e.cachedStack()
+	if stack == nil {
+		return e.summary()
+	}
+	summary := e.summary()
+	var b strings.Builder
+	b.Grow(len(summary) + len(" | Stack: ") + len(stack))
+	b.WriteString(summary)
+	b.WriteString(" | Stack: ")
+	b.Write(stack)
+	return b.String()
+}
+
+// Implements Err
+func (e *err) LogStringCompact() string {
+	frames := e.StackFrames()
+	if len(frames) == 0 {
+		return escapeNewlines(e.summary())
+	}
+	var b strings.Builder
+	for i, frame := range frames {
+		if i > 0 {
+			b.WriteString(" <- ")
+		}
+		b.WriteString(frame.Func)
+		b.WriteByte(' ')
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+	}
+	return escapeNewlines(e.summary()) + " | Stack: " + truncateStack(b.String())
+}
+
+// Get the LogString without the stack
+func (e *err) summary() string {
+	if e.chained {
+		return e.view().summary()
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.goroutineID != 0 {
+		return concatArgs(e.levelLocked(),
+			"| Time:", e.time,
+			"| Goroutine:", e.goroutineID,
+			"| Code:", e.code,
+			"| StdError:", e.wrappedErrStr(),
+			"| Info:["+filterInfo(e.info).String()+"]",
+			"| PublicMsg:", e.publicMsgStr(),
+		)
+	}
+	return concatArgs(e.levelLocked(),
 		"| Time:", e.time,
+		"| Code:", e.code,
 		"| StdError:", e.wrappedErrStr(),
-		"| Info:["+concatArgs(e.info)+"]",
-		"| PublicMsg:", e.publicMsg,
-		"| Stack:", string(e.stack),
+		"| Info:["+filterInfo(e.info).String()+"]",
+		"| PublicMsg:", e.publicMsgStr(),
 	)
 }
 
-// Merge in the given info and public message parts into this error
-func (e *err) mergeIn(info Info, publicMsgParts []interface{}) {
-	for key, val := range info {
-		for e.info[key] != nil {
-			key = key + "_duplicate"
-		}
-		e.info[key] = val
+// Merge in the given code, info and public message parts into this error.
+// The code is only set if it is non-empty.
+func (e *err) mergeIn(code string, info Info, publicMsgParts []interface{}) {
+	info, publicMsgParts = splitInfoArgs(info, publicMsgParts)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if code != "" {
+		e.code = code
 	}
-	publicMsgPrefix := concatArgs(publicMsgParts...)
-	if publicMsgPrefix == "" {
-		// do nothing
-	} else if e.publicMsg == "" {
-		e.publicMsg = publicMsgPrefix
-	} else {
-		e.publicMsg = publicMsgPrefix + " - " + e.publicMsg
+	if e.info == nil {
+		e.info = Info{}
+	}
+	mergeInfo(e.info, info)
+	e.publicMsg = mergePublicMsg(appendPublicMsg(nil, publicMsgParts), e.publicMsg)
+}
+
+// Get a copy of the error, with a copy of its info. Chained errors are flattened.
+func (e *err) clone() *err {
+	v := e.view()
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	c := &err{
+		pcs:          v.pcs,
+		time:         v.time,
+		goroutineID:  v.goroutineID,
+		wrappedErr:   v.wrappedErr,
+		isUserErr:    v.isUserErr,
+		info:         copyInfo(v.info),
+		publicMsg:    append([]string{}, v.publicMsg...),
+		code:         v.code,
+		httpStatus:   v.httpStatus,
+		retryable:    v.retryable,
+		retryableSet: v.retryableSet,
+		level:        v.level,
+		numMerged:    v.numMerged,
+		allStacks:    v.allStacks,
+
+		localizationKey:  v.localizationKey,
+		localizationArgs: v.localizationArgs,
 	}
+	if c.pcs == nil {
+		// The stack may have been decoded rather than captured
+		c.stackOnce.Do(func() { c.stack = v.cachedStack() })
+	}
+	return c
+}
+
+// Get a new error with the given public message, and the code, HTTP status
+// and user error flag of this error. Chained errors are flattened.
+func (e *err) sanitize(publicMsg []string) *err {
+	v := e.view()
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return &err{
+		time:       v.time,
+		isUserErr:  v.isUserErr,
+		info:       Info{},
+		publicMsg:  publicMsg,
+		code:       v.code,
+		httpStatus: v.httpStatus,
+
+		localizationKey:  v.localizationKey,
+		localizationArgs: v.localizationArgs,
+	}
+}
+
+// Count a Wrap which was merged into this error
+func (e *err) addMerged() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.numMerged++
+}
+
+// Get a flattened view of the error, which aggregates all of its chained
+// layers as if Wrap had merged them. Unchained errors are returned as is.
+func (e *err) view() *err {
+	if !e.chained {
+		return e
+	}
+	inner := e.wrappedErr.(Err)
+	innerBase := inner.(baser).base().view()
+	// Get these before locking innerBase, since they lock it too
+	wrappedErr, isUserErr, publicMsg := inner.WrappedError(), inner.IsUserError(), inner.PublicMsgParts()
+	innerBase.mu.RLock()
+	v := &err{
+		pcs:          e.pcs,
+		time:         e.time,
+		goroutineID:  e.goroutineID,
+		wrappedErr:   wrappedErr,
+		isUserErr:    e.isUserErr || isUserErr,
+		info:         copyInfo(innerBase.info),
+		publicMsg:    publicMsg,
+		code:         innerBase.code,
+		httpStatus:   innerBase.httpStatus,
+		retryable:    innerBase.retryable,
+		retryableSet: innerBase.retryableSet,
+		level:        innerBase.level,
+
+		localizationKey:  innerBase.localizationKey,
+		localizationArgs: innerBase.localizationArgs,
+	}
+	innerBase.mu.RUnlock()
+	if v.info == nil {
+		v.info = Info{}
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.code != "" {
+		v.code = e.code
+	}
+	if e.httpStatus != 0 {
+		v.httpStatus = e.httpStatus
+	}
+	if e.retryableSet {
+		v.retryable, v.retryableSet = e.retryable, true
+	}
+	if e.level != 0 {
+		v.level = e.level
+	}
+	mergeInfo(v.info, e.info)
+	v.publicMsg = mergePublicMsg(append([]string{}, e.publicMsg...), v.publicMsg)
+	return v
+}
+
+// Get the joined public message. The caller must hold e.mu
+func (e *err) publicMsgStr() string {
+	return joinStrings(e.publicMsg)
 }
 
 // Get the string representation of the wrapper error,
@@ -199,9 +1162,144 @@ func (e *err) wrappedErrStr() string {
 	return e.wrappedErr.Error()
 }
 
-// Helper to concatenate arguments into a string,
-// with spaces between the arguments
+// The suffix added to colliding info keys by MergeSuffix
+const duplicateSuffix = "_duplicate"
+
+// Merge src into dst. Keys which are already in dst
+// are merged according to the policy set with SetMergePolicy.
+func mergeInfo(dst, src Info) {
+	policy := MergePolicy(mergePolicy.Load()) &^ MergeReplacePublic
+	for key, val := range src {
+		switch policy {
+		case MergeOverwrite:
+			// use key as is
+		case MergeKeepFirst:
+			if _, exists := dst[key]; exists {
+				continue
+			}
+		default:
+			for {
+				if _, exists := dst[key]; !exists {
+					break
+				}
+				key = key + duplicateSuffix
+			}
+		}
+		dst[key] = val
+	}
+}
+
+// Move any Info values in publicMsgParts into a copy of info, with later
+// keys taking precedence. If there are none, the arguments are returned as is.
+func splitInfoArgs(info Info, publicMsgParts []interface{}) (Info, []interface{}) {
+	var merged Info
+	var parts []interface{}
+	for i, part := range publicMsgParts {
+		partInfo, isInfo := part.(Info)
+		if !isInfo {
+			if merged != nil {
+				parts = append(parts, part)
+			}
+			continue
+		}
+		if merged == nil {
+			merged = copyInfo(info)
+			if merged == nil {
+				merged = Info{}
+			}
+			parts = append([]interface{}{}, publicMsgParts[:i]...)
+		}
+		for key, val := range partInfo {
+			merged[key] = val
+		}
+	}
+	if merged == nil {
+		return info, publicMsgParts
+	}
+	return merged, parts
+}
+
+// Merge the outer public message parts with the inner ones,
+// according to MergeReplacePublic
+func mergePublicMsg(outer, inner []string) []string {
+	if len(outer) > 0 && MergePolicy(mergePolicy.Load())&MergeReplacePublic != 0 {
+		return outer
+	}
+	return append(outer, inner...)
+}
+
+// Get a copy of info, or nil if info is nil
+func copyInfo(info Info) Info {
+	if info == nil {
+		return nil
+	}
+	res := make(Info, len(info))
+	for key, val := range info {
+		res[key] = val
+	}
+	return res
+}
+
+// Helper to escape newlines, such that str fits on a single line
+func escapeNewlines(str string) string {
+	if !strings.ContainsAny(str, "\r\n") {
+		return str
+	}
+	return strings.NewReplacer("\r", `\r`, "\n", `\n`).Replace(str)
+}
+
+// Helper to join the non-empty strings with the public message delimiter
+func joinStrings(strs []string) string {
+	nonEmpty := strs[:0:0]
+	for _, str := range strs {
+		if str != "" {
+			nonEmpty = append(nonEmpty, str)
+		}
+	}
+	delimiter := " - "
+	if custom := publicMsgDelimiter.Load(); custom != nil {
+		delimiter = *custom
+	}
+	return strings.Join(nonEmpty, delimiter)
+}
+
+// Append the concatenated publicMsgParts to publicMsg, unless they are empty
+func appendPublicMsg(publicMsg []string, publicMsgParts []interface{}) []string {
+	if part := concatArgs(publicMsgParts...); part != "" {
+		return append(publicMsg, part)
+	}
+	return publicMsg
+}
+
+// Helper to concatenate arguments into a string, with spaces
+// between the arguments. The output is identical to fmt.Sprintln
+// without its trailing newline, but with fewer allocations.
 func concatArgs(args ...interface{}) string {
-	res := fmt.Sprintln(args...)
-	return res[0 : len(res)-1] // Remove newline at the end
+	size := len(args)
+	for _, arg := range args {
+		if str, isStr := arg.(string); isStr {
+			size += len(str)
+		} else {
+			size += 64
+		}
+	}
+	var b strings.Builder
+	b.Grow(size)
+	for i, arg := range args {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		switch val := arg.(type) {
+		case string:
+			b.WriteString(val)
+		case int:
+			b.WriteString(strconv.Itoa(val))
+		default:
+			// Format into a stack buffer, since passing &b to
+			// fmt.Fprint would make b escape to the heap.
+			var scratch [64]byte
+			b.Write(fmt.Append(scratch[:0], arg))
+		}
+	}
+	return b.String()
 }
@@ -1,9 +1,17 @@
 package errs_test
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/marcuswestin/go-errs"
 )
@@ -16,6 +24,23 @@ func TestNew(t *testing.T) {
 	assert(t, err.PublicMsg() == "", "Expected no public message")
 }
 
+func TestSetClock(t *testing.T) {
+	fixedTime := time.Date(2015, 10, 21, 16, 29, 0, 0, time.UTC)
+	errs.SetClock(func() time.Time { return fixedTime })
+	defer errs.SetClock(nil)
+	assert(t, errs.New(nil).Time().Equal(fixedTime))
+	assert(t, errs.Wrap(io.EOF, nil).Time().Equal(fixedTime))
+	errs.SetClock(nil)
+	assert(t, !errs.New(nil).Time().Equal(fixedTime))
+}
+
+func TestTimeUTC(t *testing.T) {
+	err := errs.New(nil)
+	assert(t, err.TimeUTC().Location() == time.UTC)
+	assert(t, err.TimeUTC().Equal(err.Time()))
+	assert(t, !strings.Contains(err.TimeUTC().String(), "m="), "Expected no monotonic clock reading")
+}
+
 func TestInfo(t *testing.T) {
 	err := errs.New(errs.Info{"Foo": "Bar"})
 	assert(t, err.Info("Foo") == "Bar", "Expected info Foo to be Bar")
@@ -28,6 +53,56 @@ func TestWrap(t *testing.T) {
 	assert(t, err.WrappedError().Error() == "It broke!", "Expected wrapped error message to be It broke!")
 }
 
+func TestWrappedErrors(t *testing.T) {
+	inner := fmt.Errorf("inner: %w", io.EOF)
+	middle := errs.Wrap(inner, nil)
+	outer := fmt.Errorf("outer: %w", middle)
+	err := errs.Wrap(outer, nil)
+	chain := err.WrappedErrors()
+	assert(t, len(chain) == 4, "Expected 4 wrapped errors, got", len(chain))
+	assert(t, chain[0] == outer)
+	assert(t, chain[1] == middle)
+	assert(t, chain[2] == inner)
+	assert(t, chain[3] == io.EOF)
+	assert(t, len(errs.New(nil).WrappedErrors()) == 0)
+}
+
+func TestCause(t *testing.T) {
+	assert(t, errs.Wrap(errs.Wrap(io.EOF, nil), nil).Cause() == io.EOF)
+	wrapped := fmt.Errorf("wrapped: %w", io.EOF)
+	assert(t, errs.Wrap(wrapped, nil).Cause() == wrapped)
+	assert(t, errs.New(nil).Cause() == nil)
+}
+
+type fakeNetErr struct{ timeout, temporary bool }
+
+func (e fakeNetErr) Error() string   { return "fakeNetErr" }
+func (e fakeNetErr) Timeout() bool   { return e.timeout }
+func (e fakeNetErr) Temporary() bool { return e.temporary }
+
+func TestNetError(t *testing.T) {
+	netErr, isNetErr := errs.Wrap(fakeNetErr{timeout: true}, nil).(net.Error)
+	assert(t, isNetErr, "Expected errs.Err to implement net.Error")
+	assert(t, netErr.Timeout())
+	assert(t, !netErr.Temporary())
+	netErr = errs.Wrap(errs.Wrap(fakeNetErr{temporary: true}, nil), nil).(net.Error)
+	assert(t, !netErr.Timeout())
+	assert(t, netErr.Temporary())
+	netErr = errs.Wrap(io.EOF, nil).(net.Error)
+	assert(t, !netErr.Timeout() && !netErr.Temporary())
+}
+
+func TestWrapf(t *testing.T) {
+	err := errs.Wrapf(io.EOF, nil, "Failed to open %s", "file.txt")
+	assert(t, err.PublicMsg() == "Failed to open file.txt")
+	assert(t, err.WrappedError() == io.EOF)
+
+	err = errs.Wrapf(errs.New(errs.Info{}, "Inner"), errs.Info{"Foo": "Bar"}, "Outer %d", 1)
+	assert(t, err.PublicMsg() == "Outer 1 - Inner")
+	assert(t, err.Info("Foo") == "Bar")
+	assert(t, errs.Wrapf(nil, nil, "Nil") == nil)
+}
+
 func TestWrapNil(t *testing.T) {
 	err := errs.Wrap(nil, nil)
 	assert(t, err == nil, "Expected nil-wrapped err to be nil")
@@ -38,6 +113,13 @@ func TestNilInfo(t *testing.T) {
 	assert(t, err.Info("Foo") == nil)
 }
 
+func TestWrapNilInfo(t *testing.T) {
+	err := errs.Wrap(errs.New(nil), errs.Info{"Foo": "Bar"})
+	assert(t, err.Info("Foo") == "Bar")
+	err = errs.NewNoStack(nil).WithInfo("Cat", "Mat")
+	assert(t, err.Info("Cat") == "Mat")
+}
+
 func TestAllInfo(t *testing.T) {
 	err := errs.New(errs.Info{"Foo": "Bar"})
 	err = errs.Wrap(err, errs.Info{"Cat": "Mat"})
@@ -46,6 +128,15 @@ func TestAllInfo(t *testing.T) {
 	assert(t, err.AllInfo()["Woot"] == nil)
 }
 
+func TestAllInfoCopy(t *testing.T) {
+	err := errs.New(errs.Info{"Foo": "Bar"})
+	info := err.AllInfo()
+	info["Foo"] = "Changed"
+	info["loggedAt"] = "now"
+	assert(t, err.Info("Foo") == "Bar", "Expected mutating AllInfo to not affect the error")
+	assert(t, err.Info("loggedAt") == nil, "Expected mutating AllInfo to not affect the error")
+}
+
 func TestMultiWrap(t *testing.T) {
 	publicMsg := "publicMsg"
 	err := errs.New(errs.Info{"Key": "First"}, publicMsg)
@@ -57,9 +148,377 @@ func TestMultiWrap(t *testing.T) {
 	assert(t, err.PublicMsg() == strings.Join([]string{publicMsg, publicMsg, publicMsg}, " - "))
 }
 
+func TestRetryable(t *testing.T) {
+	assert(t, !errs.New(nil).Retryable(), "Expected errors to not be retryable by default")
+	assert(t, !errs.Wrap(io.EOF, nil).Retryable(), "Expected errors to not be retryable by default")
+	assert(t, errs.Retryable(nil, "Try again").Retryable())
+	assert(t, errs.Wrap(errs.Retryable(nil), nil).Retryable(), "Expected retryable to survive Wrap")
+	assert(t, errs.Wrap(io.EOF, nil).WithRetryable(true).Retryable())
+	err := errs.Wrap(errs.Retryable(nil), nil).WithRetryable(false)
+	assert(t, !errs.Wrap(err, nil).Retryable(), "Expected WithRetryable(false) to override")
+}
+
+func TestPublicMsgf(t *testing.T) {
+	assert(t, errs.PublicMsgf("%s:%d", "x", 5).PublicMsg() == "x:5")
+	assert(t, errs.New(nil, "x", ":", 5).PublicMsg() == "x : 5")
+	err := errs.PublicMsgf("%d%%", 50).WithInfo("Foo", "Bar")
+	assert(t, err.PublicMsg() == "50%")
+	assert(t, err.Info("Foo") == "Bar")
+}
+
+func TestFormatPublicMsg(t *testing.T) {
+	err := errs.Format(errs.Info{"Foo": "Bar"}, "bad %d", 5)
+	assert(t, err.PublicMsg() == "bad 5", "Expected Format to set the public message")
+	assert(t, err.WrappedError().Error() == "bad 5")
+	assert(t, err.Info("Foo") == "Bar")
+}
+
+func TestWrapIf(t *testing.T) {
+	var err error = errs.WrapIf(nil, errs.Info{"Foo": "Bar"}, "publicMsg")
+	assert(t, err == nil, "Expected nil for a nil error")
+	err = errs.WrapIf(io.EOF, errs.Info{"Foo": "Bar"}, "publicMsg")
+	assert(t, err != nil && errors.Is(err, io.EOF), "Expected the error to be wrapped")
+	assert(t, err.(errs.Err).PublicMsg() == "publicMsg" && err.(errs.Err).Info("Foo") == "Bar")
+	assert(t, strings.Contains(string(err.(errs.Err).Stack()), "TestWrapIf"), "Expected the stack to start at the caller")
+}
+
+func TestNewIf(t *testing.T) {
+	var err error = errs.NewIf(false, nil, "publicMsg")
+	assert(t, err == nil, "Expected nil for a false condition")
+	err = errs.NewIf(true, errs.Info{"Foo": "Bar"}, "publicMsg")
+	assert(t, err != nil && err.(errs.Err).PublicMsg() == "publicMsg" && err.(errs.Err).Info("Foo") == "Bar")
+	assert(t, strings.Contains(string(err.(errs.Err).Stack()), "TestNewIf"), "Expected the stack to start at the caller")
+}
+
+func TestErrorf(t *testing.T) {
+	err := errs.Errorf(errs.Info{"Path": "a.txt"}, "reading %s: %w", "a.txt", io.EOF)
+	assert(t, err.PublicMsg() == "reading a.txt: EOF", err.PublicMsg())
+	assert(t, errors.Unwrap(err) == io.EOF, "Expected the %w error to be the Unwrap target")
+	assert(t, err.Info("Path") == "a.txt")
+
+	err = errs.Errorf(nil, "%w and %w", io.EOF, io.ErrClosedPipe)
+	assert(t, err.PublicMsg() == "EOF and io: read/write on closed pipe", err.PublicMsg())
+	assert(t, errors.Is(err, io.EOF) && errors.Is(err, io.ErrClosedPipe), "Expected both %w errors to match")
+
+	inner := errs.New(errs.Info{"Password": "hunter2"}, "inner")
+	err = errs.Errorf(nil, "reading %s: %w", "file", inner)
+	assert(t, err.PublicMsg() == "reading file: inner", "Expected the errs.Err to be formatted with its PublicMsg", err.PublicMsg())
+	assert(t, errors.Unwrap(err) == inner, "Expected the errs.Err to be the Unwrap target")
+
+	err = errs.Errorf(nil, "no cause %d", 5)
+	assert(t, err.PublicMsg() == "no cause 5" && err.WrappedError() == nil)
+}
+
+func TestFormatErrsArgs(t *testing.T) {
+	dbErr := errs.NewWithCode("DB_DOWN", errs.Info{"Password": "hunter2"}, "Database unavailable")
+	for _, err := range []errs.Err{
+		errs.Format(nil, "failed: %v", dbErr),
+		errs.Wrapf(io.EOF, nil, "failed: %v", dbErr),
+		errs.PublicMsgf("failed: %v", dbErr),
+	} {
+		assert(t, err.PublicMsg() == "failed: Database unavailable", "Expected the errs.Err argument to be formatted with its PublicMsg", err.PublicMsg())
+		assert(t, !strings.Contains(err.PublicMsg(), "hunter2"), "Expected no internal info in PublicMsg", err.PublicMsg())
+	}
+	format := errs.Format(nil, "failed: %w", dbErr)
+	assert(t, errors.Is(format, dbErr), "Expected Format to still wrap the errs.Err")
+}
+
+func TestSetAutoClassify(t *testing.T) {
+	transient := []error{
+		context.DeadlineExceeded,
+		io.ErrUnexpectedEOF,
+		fakeNetErr{temporary: true},
+		fmt.Errorf("reading: %w", io.ErrUnexpectedEOF),
+	}
+	for _, err := range transient {
+		assert(t, !errs.Wrap(err, nil).Retryable(), "Expected no classification by default", err)
+	}
+	errs.SetAutoClassify(true)
+	defer errs.SetAutoClassify(false)
+	for _, err := range transient {
+		assert(t, errs.Wrap(err, nil).Retryable(), "Expected transient error to be retryable", err)
+	}
+	assert(t, !errs.Wrap(errors.New("plain"), nil).Retryable(), "Expected plain error not to be retryable")
+	assert(t, !errs.Wrap(fakeNetErr{timeout: true}, nil).Retryable(), "Expected non-temporary net.Error not to be retryable")
+}
+
+func TestDepth(t *testing.T) {
+	assert(t, errs.New(nil).Depth() == 1, "Expected depth 1 for a new error")
+	err := errs.Wrap(io.EOF, nil)
+	err = errs.Wrap(err, nil)
+	err = errs.Wrap(err, errs.Info{"Foo": "Bar"})
+	err.WithInfo("Cat", "Mat")
+	assert(t, err.Depth() == 3, "Expected depth 3", err.Depth())
+
+	errs.SetPreserveChain(true)
+	defer errs.SetPreserveChain(false)
+	err = errs.Wrap(errs.Wrap(errs.New(nil), nil), nil)
+	assert(t, err.Depth() == 3, "Expected depth 3 with preserved chain", err.Depth())
+}
+
+func TestMaxWrapDepth(t *testing.T) {
+	errs.SetMaxWrapDepth(10)
+	defer errs.SetMaxWrapDepth(0)
+	err := errs.New(nil)
+	for i := 0; i < 1000; i++ {
+		err = errs.Wrap(err, errs.Info{"Attempt": i}, "Retry failed")
+	}
+	assert(t, len(err.AllInfo()) <= 10, "Expected info to stay bounded", len(err.AllInfo()))
+	assert(t, len(err.PublicMsgParts()) <= 10, "Expected public message to stay bounded", len(err.PublicMsgParts()))
+	assert(t, err.Info("Attempt") == 0, "Expected the first info to be kept")
+}
+
+func TestIsSentinels(t *testing.T) {
+	userErr := errs.UserError(nil, "Wrong password")
+	assert(t, errors.Is(userErr, errs.ErrUser), "Expected user error to match ErrUser")
+	assert(t, !errors.Is(userErr, errs.ErrRetryable))
+	assert(t, errors.Is(fmt.Errorf("wrapped: %w", userErr), errs.ErrUser), "Expected wrapped user error to match ErrUser")
+
+	retryableErr := errs.Retryable(nil)
+	assert(t, errors.Is(retryableErr, errs.ErrRetryable), "Expected retryable error to match ErrRetryable")
+	assert(t, !errors.Is(retryableErr, errs.ErrUser))
+
+	err := errs.New(nil)
+	assert(t, !errors.Is(err, errs.ErrUser) && !errors.Is(err, errs.ErrRetryable), "Expected no match for a plain errs.Err")
+	assert(t, !errors.Is(errors.New("plain"), errs.ErrUser))
+	assert(t, errors.Is(errs.Wrap(io.EOF, nil), io.EOF), "Expected other targets to still match")
+}
+
+func TestInfof(t *testing.T) {
+	err := errs.New(errs.Infof("q", "%d", 5))
+	assert(t, err.Info("q") == "5", err.Info("q"))
+
+	info := errs.Info{"Foo": "Bar"}
+	err = errs.New(info, "Failed", errs.Infof("q", "%s=%v", "id", 7), "query", errs.Info{"Foo": "Override"})
+	assert(t, err.Info("q") == "id=7", err.Info("q"))
+	assert(t, err.Info("Foo") == "Override", "Expected later info to take precedence")
+	assert(t, err.PublicMsg() == "Failed query", "Expected info args to be removed from the public message", err.PublicMsg())
+	assert(t, len(info) == 1, "Expected the given info not to be modified")
+
+	err = errs.Wrap(err, nil, errs.Infof("Retry", "%d", 2), "Outer")
+	assert(t, err.Info("Retry") == "2" && err.PublicMsg() == "Outer - Failed query", err.PublicMsg())
+}
+
+func TestUserErrorWrap(t *testing.T) {
+	parseErr := errors.New("parsing time")
+	err := errs.UserErrorWrap(parseErr, errs.Info{"Input": "2020-13"}, "Invalid date")
+	assert(t, err.IsUserError(), "Expected a user error")
+	assert(t, err.WrappedError() == parseErr, "Expected the wrapped error")
+	assert(t, err.PublicMsg() == "Invalid date" && err.Info("Input") == "2020-13")
+
+	inner := errs.New(errs.Info{"Foo": "Bar"}, "Inner")
+	err = errs.UserErrorWrap(inner, nil, "Outer")
+	assert(t, err.IsUserError() && !inner.IsUserError(), "Expected only the outer error to be a user error")
+	assert(t, errors.Is(err, inner) && err.Info("Foo") == "Bar" && err.PublicMsg() == "Outer - Inner", err.PublicMsg())
+
+	assert(t, errs.UserErrorWrap(nil, nil) == nil, "Expected nil for a nil error")
+}
+
+func TestUnwrap(t *testing.T) {
+	assert(t, errors.Is(errs.Wrap(sql.ErrNoRows, nil), sql.ErrNoRows))
+	assert(t, errors.Unwrap(errs.Wrap(io.EOF, nil)) == io.EOF)
+	assert(t, !errors.Is(errs.New(nil), io.EOF))
+}
+
+func TestUnwrapMultiWrap(t *testing.T) {
+	err := errs.Wrap(sql.ErrNoRows, errs.Info{"Key": "First"})
+	err = errs.Wrap(err, errs.Info{"Key": "Second"})
+	err = errs.Wrap(err, nil, "publicMsg")
+	assert(t, errors.Is(err, sql.ErrNoRows))
+	assert(t, errors.Unwrap(err) == sql.ErrNoRows)
+
+	_, openErr := os.Open("/does/not/exist")
+	err = errs.Wrap(errs.Wrap(openErr, nil), nil)
+	var pathErr *os.PathError
+	assert(t, errors.As(err, &pathErr), "Expected errors.As to find *os.PathError")
+	assert(t, pathErr.Path == "/does/not/exist")
+}
+
+func TestCode(t *testing.T) {
+	assert(t, errs.NewWithCode("USER_EMAIL_TAKEN", nil).Code() == "USER_EMAIL_TAKEN")
+	assert(t, errs.New(nil).Code() == "")
+
+	err := errs.Wrap(errs.NewWithCode("INNER", nil), nil)
+	assert(t, err.Code() == "INNER", "Expected inner code to survive Wrap")
+	err = errs.WrapWithCode("OUTER", err, nil)
+	assert(t, err.Code() == "OUTER", "Expected outer code to replace inner code")
+	assert(t, errs.WrapWithCode("STD", io.EOF, nil).Code() == "STD")
+	assert(t, strings.Contains(err.LogString(), "Code: OUTER"))
+}
+
+func TestHTTPStatus(t *testing.T) {
+	assert(t, errs.New(nil).HTTPStatus() == 500)
+	assert(t, errs.UserError(nil, "bad").HTTPStatus() == 400)
+	assert(t, errs.HTTPError(404, nil, "Not found").HTTPStatus() == 404)
+	err := errs.Wrap(errs.HTTPError(409, nil), nil, "Conflict")
+	assert(t, err.HTTPStatus() == 409, "Expected inner status to survive Wrap")
+}
+
+func TestWithStatus(t *testing.T) {
+	err := errs.Wrap(errors.New("db down"), nil).WithStatus(503)
+	assert(t, err.HTTPStatus() == 503, err.HTTPStatus())
+	err = errs.Wrap(err, nil, "Try again later")
+	assert(t, err.HTTPStatus() == 503, "Expected status to survive Wrap")
+	assert(t, err.WithStatus(504).HTTPStatus() == 504, "Expected status to be changeable")
+	assert(t, errs.UserError(nil).WithStatus(401).HTTPStatus() == 401)
+
+	errs.SetPreserveChain(true)
+	defer errs.SetPreserveChain(false)
+	err = errs.Wrap(errs.HTTPError(409, nil), nil)
+	assert(t, err.HTTPStatus() == 409, "Expected inner status to survive a chained Wrap")
+	assert(t, err.WithStatus(503).HTTPStatus() == 503, "Expected outer status to override inner status")
+}
+
+func TestConcurrentWrap(t *testing.T) {
+	err := errs.New(errs.Info{"Key": "Value"})
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs.Wrap(err, errs.Info{"Key": i}, "publicMsg")
+			_ = err.AllInfo()
+			_ = err.LogString()
+		}(i)
+	}
+	wg.Wait()
+	assert(t, len(err.AllInfo()) == 51, "Expected all info to be merged in")
+}
+
+func TestConcurrentChainedWrap(t *testing.T) {
+	errs.SetPreserveChain(true)
+	defer errs.SetPreserveChain(false)
+	inner := errs.New(errs.Info{"Key": "Value"}, "inner")
+	outer := errs.Wrap(inner, nil, "outer")
+	joinedOuter := errs.Wrap(errs.Join(inner), nil, "outer")
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				inner.WithStatus(400 + j)
+				errs.Wrap(inner, errs.Info{"Key": i})
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = outer.PublicMsg()
+				_ = outer.LogString()
+				_ = joinedOuter.PublicMsg()
+			}
+		}()
+	}
+	wg.Wait()
+	assert(t, outer.PublicMsg() == "outer - inner", outer.PublicMsg())
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		errs.New(nil)
+	}
+}
+
+func BenchmarkNewWithStack(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		errs.New(nil).Stack()
+	}
+}
+
+func TestPublicMsgParts(t *testing.T) {
+	err := errs.New(nil, "first")
+	err = errs.Wrap(err, nil)
+	err = errs.Wrap(err, nil, "second")
+	parts := err.PublicMsgParts()
+	assert(t, len(parts) == 2 && parts[0] == "second" && parts[1] == "first", "Expected outermost part first", parts)
+	assert(t, err.PublicMsg() == "second - first")
+	parts[0] = "changed"
+	assert(t, err.PublicMsgParts()[0] == "second", "Expected PublicMsgParts to return a copy")
+	assert(t, len(errs.New(nil).PublicMsgParts()) == 0)
+}
+
+func TestSetPublicMsgDelimiter(t *testing.T) {
+	errs.SetPublicMsgDelimiter("; ")
+	defer errs.SetPublicMsgDelimiter(" - ")
+	err := errs.Wrap(errs.New(nil, "Inner"), nil, "Outer")
+	assert(t, err.PublicMsg() == "Outer; Inner", err.PublicMsg())
+	assert(t, errs.Join(errs.New(nil, "a"), errs.New(nil, "b")).PublicMsg() == "a; b")
+}
+
+func TestPreserveChain(t *testing.T) {
+	errs.SetPreserveChain(true)
+	defer errs.SetPreserveChain(false)
+
+	inner := newInnerErr()
+	outer := errs.Wrap(inner, errs.Info{"Key": "Outer", "Cat": "Mat"}, "Outer")
+	assert(t, outer != inner, "Expected Wrap to create a new layer")
+	assert(t, outer.StackFrames()[0].Line != inner.StackFrames()[0].Line, "Expected inner and outer stacks to differ")
+	assert(t, strings.HasSuffix(inner.StackFrames()[0].Func, ".newInnerErr"))
+	assert(t, strings.HasSuffix(outer.StackFrames()[0].Func, ".TestPreserveChain"))
+
+	assert(t, outer.PublicMsg() == "Outer - Inner")
+	assert(t, outer.Info("Key") == "Inner")
+	assert(t, outer.Info("Key_duplicate") == "Outer")
+	assert(t, outer.Info("Cat") == "Mat")
+	assert(t, len(outer.AllInfo()) == 3)
+	assert(t, outer.Code() == "INNER")
+	assert(t, outer.HTTPStatus() == 500)
+	assert(t, outer.WrappedError() == io.EOF)
+	assert(t, errors.Is(outer, io.EOF))
+	assert(t, errors.Unwrap(outer) == inner, "Expected Unwrap to return the inner layer")
+	assert(t, strings.Contains(outer.LogString(), "PublicMsg: Outer - Inner"))
+	assert(t, inner.PublicMsg() == "Inner", "Expected the inner layer to be unchanged")
+	assert(t, inner.Info("Cat") == nil, "Expected the inner layer to be unchanged")
+
+	outer = errs.WrapWithCode("OUTER", outer, nil)
+	assert(t, outer.Code() == "OUTER")
+	assert(t, !outer.Retryable())
+	inner.WithRetryable(true)
+	assert(t, outer.Retryable(), "Expected a retryable inner layer to make the chain retryable")
+	outer.WithRetryable(false)
+	assert(t, !outer.Retryable(), "Expected an outer layer to override retryable")
+}
+
+func newInnerErr() errs.Err {
+	return errs.WrapWithCode("INNER", io.EOF, errs.Info{"Key": "Inner"}, "Inner")
+}
+
 func assert(t *testing.T, ok bool, msg ...interface{}) {
 	if !ok {
 		panic(msg)
 		// t.Fatal(msg...)
 	}
 }
+
+func TestLogStringCompact(t *testing.T) {
+	err := errs.Wrap(errors.New("line1\nline2"), errs.Info{"SQL": "SELECT *\nFROM users"}, "multi\nline")
+	compact := err.LogStringCompact()
+	assert(t, !strings.Contains(compact, "\n"), "Expected no newlines", compact)
+	assert(t, strings.Contains(compact, `SQL=SELECT *\nFROM users`), "Expected escaped newlines", compact)
+	assert(t, strings.Contains(compact, "PublicMsg: multi\\nline | Stack: "), compact)
+	assert(t, strings.Contains(compact, "TestLogStringCompact "), "Expected stack frames", compact)
+	assert(t, strings.Contains(compact, " <- "), "Expected frame separators", compact)
+
+	compact = errs.Join(err, errors.New("a\nb")).LogStringCompact()
+	assert(t, !strings.Contains(compact, "\n"), "Expected no newlines in joined errors", compact)
+	assert(t, !strings.Contains(errs.NewNoStack(nil).LogStringCompact(), "Stack:"))
+}
+
+func TestConcatArgs(t *testing.T) {
+	args := []interface{}{"Email", "foo@bar.com", 42, map[string]int{"b": 2, "a": 1}, nil, []string{"x"}, ""}
+	expected := fmt.Sprintln(args...)
+	expected = expected[:len(expected)-1]
+	publicMsg := errs.New(nil, args...).PublicMsg()
+	assert(t, publicMsg == expected, "Expected output identical to fmt.Sprintln", publicMsg, expected)
+}
+
+func BenchmarkLogString(b *testing.B) {
+	err := errs.Wrap(errors.New("std"), errs.Info{"Foo": "Bar", "Num": 1}, "Email", "foo@bar.com", "is taken")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.LogString()
+	}
+}
@@ -0,0 +1,98 @@
+// Package errsgrpc converts errs.Errs into gRPC statuses, so that
+// clients receive a meaningful code and a user-facing message:
+//
+//	if err != nil {
+//		return nil, errsgrpc.ToStatus(err).Err()
+//	}
+package errsgrpc
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/marcuswestin/go-errs"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ToStatus converts err into a gRPC status. Retryable errors get the code
+// Unavailable, and other errors get the code which corresponds to their
+// HTTPStatus, e.g InvalidArgument for user errors and Internal for system
+// errors. The message is the PublicMsg, and the errs code is attached as
+// an errdetails.ErrorInfo with the code as its Reason. Its Metadata holds
+// the info keys given to SetMetadataKeys, rendered as for LogString.
+// If err is nil, ToStatus returns an OK status.
+func ToStatus(err errs.Err) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	st := status.New(Code(err), err.PublicMsg())
+	metadata := map[string]string{}
+	if keys := metadataKeys.Load(); keys != nil {
+		info := err.LogInfo()
+		for _, key := range *keys {
+			if val, hasKey := info[key]; hasKey {
+				metadata[key] = fmt.Sprintf("%v", val)
+			}
+		}
+	}
+	if err.Code() == "" && len(metadata) == 0 {
+		return st
+	}
+	withDetails, detailsErr := st.WithDetails(&errdetails.ErrorInfo{Reason: err.Code(), Metadata: metadata})
+	if detailsErr != nil {
+		return st
+	}
+	return withDetails
+}
+
+// SetMetadataKeys sets the info keys which ToStatus includes in the
+// ErrorInfo metadata sent to clients, e.g `errsgrpc.SetMetadataKeys("RequestID")`.
+// The default is none, so that no internal info is leaked to clients by
+// accident, like with errs.SetProblemInfoKeys.
+func SetMetadataKeys(keys ...string) {
+	keys = append([]string{}, keys...)
+	metadataKeys.Store(&keys)
+}
+
+// Code returns the gRPC code for err. See ToStatus
+func Code(err errs.Err) codes.Code {
+	if err.Retryable() {
+		return codes.Unavailable
+	}
+	switch err.HTTPStatus() {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499: // Client Closed Request
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if err.IsUserError() {
+		return codes.InvalidArgument
+	}
+	return codes.Internal
+}
+
+// Internal
+///////////
+
+var metadataKeys atomic.Pointer[[]string]
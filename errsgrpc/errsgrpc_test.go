@@ -0,0 +1,48 @@
+package errsgrpc_test
+
+import (
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+	"github.com/marcuswestin/go-errs/errsgrpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	errsgrpc.SetMetadataKeys("Email", "SQL")
+	defer errsgrpc.SetMetadataKeys()
+	errs.SetMaxInfoValueLen(10)
+	defer errs.SetMaxInfoValueLen(0)
+	err := errs.NewWithCode("USER_EMAIL_TAKEN", errs.Info{"Email": "a@b.com", "SQL": "SELECT * FROM users", "UserID": 1}, "That email is already taken")
+	err = errs.UserErrorWrap(err, nil)
+	st := errsgrpc.ToStatus(err)
+	assert(t, st.Code() == codes.InvalidArgument, st.Code())
+	assert(t, st.Message() == "That email is already taken", st.Message())
+
+	fromErr, ok := status.FromError(st.Err())
+	assert(t, ok && fromErr.Code() == codes.InvalidArgument)
+	details := fromErr.Details()
+	assert(t, len(details) == 1, details)
+	errorInfo := details[0].(*errdetails.ErrorInfo)
+	assert(t, errorInfo.Reason == "USER_EMAIL_TAKEN" && errorInfo.Metadata["Email"] == "a@b.com", errorInfo)
+	assert(t, errorInfo.Metadata["SQL"] == "SELECT * F…(truncated)", "Expected truncated metadata", errorInfo)
+	_, hasUserID := errorInfo.Metadata["UserID"]
+	assert(t, !hasUserID, "Expected only the metadata keys to be included", errorInfo)
+}
+
+func TestCode(t *testing.T) {
+	assert(t, errsgrpc.Code(errs.New(nil)) == codes.Internal)
+	assert(t, errsgrpc.Code(errs.UserError(nil)) == codes.InvalidArgument)
+	assert(t, errsgrpc.Code(errs.Retryable(nil)) == codes.Unavailable)
+	assert(t, errsgrpc.Code(errs.HTTPError(404, nil)) == codes.NotFound)
+	assert(t, errsgrpc.ToStatus(nil).Code() == codes.OK)
+}
+
+func assert(t *testing.T, ok bool, msg ...interface{}) {
+	if !ok {
+		panic(msg)
+		// t.Fatal(msg...)
+	}
+}
@@ -0,0 +1,67 @@
+// Package errshttp turns errs.Errs returned by HTTP handlers into responses:
+//
+//	http.Handle("/users", errshttp.Handler(func(w http.ResponseWriter, r *http.Request) error {
+//		user, err := db.GetUser(r.FormValue("id"))
+//		if err != nil {
+//			return err
+//		}
+//		return json.NewEncoder(w).Encode(user)
+//	}))
+package errshttp
+
+import (
+	"log"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+// Handler returns an http.Handler which calls handle, and writes any returned
+// error as a response with the error's HTTPStatus and PublicJSON body. The
+// first errs.Err in the error's chain is used, e.g of an error wrapped with
+// fmt.Errorf. Errors without one are wrapped, and thus respond with status 500. The
+// LogString of errors is logged with the logger set with SetLogger, except
+// for user errors, which are expected and should not spam the error logs.
+func Handler(handle func(http.ResponseWriter, *http.Request) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := handle(w, r); err != nil {
+			errsErr, hasErr := errs.As[errs.Err](err)
+			if !hasErr {
+				errsErr = errs.Wrap(err, nil)
+			}
+			writeErr(w, errsErr)
+		}
+	})
+}
+
+// SetLogger sets the logger used by Handler to log errors.
+// Passing nil restores the default, log.Default().
+func SetLogger(logger *log.Logger) {
+	errLogger.Store(logger)
+}
+
+// Internal
+///////////
+
+// The logger set with SetLogger, or nil for log.Default()
+var errLogger atomic.Pointer[log.Logger]
+
+// Log err unless it is a user error, and write it as the response
+func writeErr(w http.ResponseWriter, err errs.Err) {
+	if !err.IsUserError() {
+		logger := errLogger.Load()
+		if logger == nil {
+			logger = log.Default()
+		}
+		logger.Print(err.LogString())
+	}
+	body, marshalErr := err.PublicJSON()
+	if marshalErr != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.HTTPStatus())
+	w.Write(body)
+}
@@ -0,0 +1,98 @@
+package errshttp_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+	"github.com/marcuswestin/go-errs/errshttp"
+)
+
+func TestHandler(t *testing.T) {
+	var logs bytes.Buffer
+	errshttp.SetLogger(log.New(&logs, "", 0))
+	defer errshttp.SetLogger(nil)
+	handler := errshttp.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		switch r.URL.Path {
+		case "/user":
+			return errs.UserError(errs.Info{"Path": r.URL.Path}, "Wrong password")
+		case "/system":
+			return errors.New("database is down")
+		case "/wrapped-user":
+			return fmt.Errorf("handling: %w", errs.UserError(nil, "Wrong password"))
+		case "/wrapped-system":
+			return fmt.Errorf("handling: %w", errs.New(nil, "Database is down"))
+		}
+		w.Write([]byte("ok"))
+		return nil
+	})
+
+	res := serve(handler, "/user")
+	assert(t, res.Code == http.StatusBadRequest, res.Code)
+	assert(t, res.Header().Get("Content-Type") == "application/json")
+	assert(t, strings.Contains(res.Body.String(), `"message":"Wrong password"`), res.Body.String())
+	assert(t, logs.Len() == 0, "Expected user errors not to be logged", logs.String())
+
+	res = serve(handler, "/system")
+	assert(t, res.Code == http.StatusInternalServerError, res.Code)
+	assert(t, !strings.Contains(res.Body.String(), "database is down"), "Expected no internal error in the body", res.Body.String())
+	assert(t, strings.Contains(logs.String(), "database is down"), "Expected system errors to be logged", logs.String())
+
+	logs.Reset()
+	res = serve(handler, "/wrapped-user")
+	assert(t, res.Code == http.StatusBadRequest, "Expected a user error wrapped by fmt to be a user error", res.Code)
+	assert(t, strings.Contains(res.Body.String(), `"message":"Wrong password"`), res.Body.String())
+	assert(t, logs.Len() == 0, "Expected wrapped user errors not to be logged", logs.String())
+
+	res = serve(handler, "/wrapped-system")
+	assert(t, res.Code == http.StatusInternalServerError, res.Code)
+	assert(t, strings.Contains(logs.String(), "Database is down"), logs.String())
+
+	res = serve(handler, "/ok")
+	assert(t, res.Code == http.StatusOK && res.Body.String() == "ok")
+}
+
+func TestHandlerNoSideEffects(t *testing.T) {
+	errshttp.SetLogger(log.New(io.Discard, "", 0))
+	defer errshttp.SetLogger(nil)
+	err := errs.New(nil, "Database is down")
+	handler := errshttp.Handler(func(w http.ResponseWriter, r *http.Request) error { return err })
+	serve(handler, "/")
+	assert(t, err.Depth() == 1, "Expected the error not to be wrapped", err.Depth())
+
+	errs.SetPreserveChain(true)
+	defer errs.SetPreserveChain(false)
+	numReported := 0
+	errs.OnError(func(errs.Err) { numReported++ })
+	serve(handler, "/")
+	assert(t, numReported == 0, "Expected no new errors to be reported", numReported)
+}
+
+func TestHandlerValidation(t *testing.T) {
+	handler := errshttp.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errs.Validation().Field("email", "already taken")
+	})
+	res := serve(handler, "/")
+	assert(t, res.Code == http.StatusBadRequest, res.Code)
+	assert(t, strings.Contains(res.Body.String(), `"fields":{"email":"already taken"}`), res.Body.String())
+}
+
+func serve(handler http.Handler, path string) *httptest.ResponseRecorder {
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest("GET", path, nil))
+	return res
+}
+
+func assert(t *testing.T, ok bool, msg ...interface{}) {
+	if !ok {
+		panic(msg)
+		// t.Fatal(msg...)
+	}
+}
@@ -0,0 +1,54 @@
+// Package errslogrus converts errs.Errs into logrus fields:
+//
+//	log.WithFields(errslogrus.Fields(err)).Error(err.PublicMsg())
+package errslogrus
+
+import (
+	"github.com/marcuswestin/go-errs"
+	"github.com/sirupsen/logrus"
+)
+
+// StackKey is the field key of the stack added by Fields
+const StackKey = "stack"
+
+// Fields returns the errs.Err's AllFields as logrus fields, with the
+// stack as a string field. Info values which are errors are converted to
+// their error strings, since logrus would otherwise render them through
+// reflection. An info key which collides with StackKey is moved into
+// the nested "info" field, like the reserved keys of AllFields.
+func Fields(err errs.Err) logrus.Fields {
+	if err == nil {
+		return logrus.Fields{}
+	}
+	fields := logrus.Fields(err.AllFields())
+	if val, collides := fields[StackKey]; collides {
+		info, _ := fields["info"].(errs.Info)
+		if info == nil {
+			info = errs.Info{}
+		}
+		info[StackKey] = val
+		fields["info"] = info
+	}
+	for key, val := range fields {
+		if info, isInfo := val.(errs.Info); isInfo {
+			for infoKey, infoVal := range info {
+				info[infoKey] = stringifyError(infoVal)
+			}
+		} else {
+			fields[key] = stringifyError(val)
+		}
+	}
+	fields[StackKey] = string(err.Stack())
+	return fields
+}
+
+// Internal
+///////////
+
+// Get the error string of val if it is an error, or else val
+func stringifyError(val interface{}) interface{} {
+	if err, isErr := val.(error); isErr && err != nil {
+		return err.Error()
+	}
+	return val
+}
@@ -0,0 +1,29 @@
+package errslogrus_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+	"github.com/marcuswestin/go-errs/errslogrus"
+)
+
+func TestFields(t *testing.T) {
+	err := errs.WrapWithCode("CODE", errors.New("std"), errs.Info{"Foo": "Bar", "Cause": errors.New("cause"), "stack": 1}, "publicMsg")
+	fields := errslogrus.Fields(err)
+	assert(t, fields["Foo"] == "Bar", fields)
+	assert(t, fields["Cause"] == "cause", "Expected error info values to be stringified", fields["Cause"])
+	assert(t, fields["code"] == "CODE" && fields["publicMsg"] == "publicMsg", fields)
+	stack, isStr := fields[errslogrus.StackKey].(string)
+	assert(t, isStr && strings.Contains(stack, "TestFields"), "Expected the stack as a string", fields[errslogrus.StackKey])
+	assert(t, fields["info"].(errs.Info)["stack"] == 1, "Expected colliding info to be namespaced", fields["info"])
+	assert(t, len(errslogrus.Fields(nil)) == 0)
+}
+
+func assert(t *testing.T, ok bool, msg ...interface{}) {
+	if !ok {
+		panic(msg)
+		// t.Fatal(msg...)
+	}
+}
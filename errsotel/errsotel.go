@@ -0,0 +1,86 @@
+// Package errsotel records errs.Errs onto OpenTelemetry spans:
+//
+//	if err != nil {
+//		errsotel.RecordError(span, err)
+//	}
+package errsotel
+
+import (
+	"fmt"
+
+	"github.com/marcuswestin/go-errs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The attribute keys set by RecordError. Info keys are prefixed with InfoKeyPrefix,
+// e.g errs.Info{"UserID": 7} is set as the attribute "errs.info.UserID".
+const (
+	CodeKey       = "errs.code"
+	PublicMsgKey  = "errs.public_msg"
+	UserErrorKey  = "errs.user_error"
+	InfoKeyPrefix = "errs.info."
+)
+
+// RecordError records err as an exception event on span, sets span
+// attributes from its code, public message and info, and marks the
+// span's status as Error. If err is nil, RecordError does nothing.
+func RecordError(span trace.Span, err errs.Err) {
+	if err == nil {
+		return
+	}
+	attrs := Attributes(err)
+	span.RecordError(err, trace.WithAttributes(attrs...))
+	span.SetAttributes(attrs...)
+	description := err.PublicMsg()
+	if description == "" {
+		description = err.Code()
+	}
+	span.SetStatus(codes.Error, description)
+}
+
+// Attributes returns the span attributes for err's code, public message
+// and info. Info values of a basic type are kept as is, while other
+// values are converted with fmt's %v. The info is rendered as for
+// LogString, see errs.Err.LogInfo.
+func Attributes(err errs.Err) []attribute.KeyValue {
+	info := err.LogInfo()
+	attrs := make([]attribute.KeyValue, 0, 3+len(info))
+	if code := err.Code(); code != "" {
+		attrs = append(attrs, attribute.String(CodeKey, code))
+	}
+	if publicMsg := err.PublicMsg(); publicMsg != "" {
+		attrs = append(attrs, attribute.String(PublicMsgKey, publicMsg))
+	}
+	attrs = append(attrs, attribute.Bool(UserErrorKey, err.IsUserError()))
+	for _, key := range err.InfoKeys() {
+		if val, isLogged := info[key]; isLogged {
+			attrs = append(attrs, infoAttribute(InfoKeyPrefix+key, val))
+		}
+	}
+	return attrs
+}
+
+// Internal
+///////////
+
+// Get the attribute for the given info value
+func infoAttribute(key string, val interface{}) attribute.KeyValue {
+	switch val := val.(type) {
+	case string:
+		return attribute.String(key, val)
+	case bool:
+		return attribute.Bool(key, val)
+	case int:
+		return attribute.Int(key, val)
+	case int64:
+		return attribute.Int64(key, val)
+	case float64:
+		return attribute.Float64(key, val)
+	case []string:
+		return attribute.StringSlice(key, val)
+	default:
+		return attribute.String(key, fmt.Sprintf("%v", val))
+	}
+}
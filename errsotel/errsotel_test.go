@@ -0,0 +1,58 @@
+package errsotel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+	"github.com/marcuswestin/go-errs/errsotel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRecordError(t *testing.T) {
+	span := &recordingSpan{Span: trace.SpanFromContext(context.Background())}
+	err := errs.WrapWithCode("CODE", errors.New("std"), errs.Info{"UserID": 7, "Name": "Bob", "Card": errs.Secret("4111")}, "publicMsg")
+	errsotel.RecordError(span, err)
+
+	assert(t, span.recorded == err, "Expected the error to be recorded")
+	assert(t, span.statusCode == codes.Error && span.statusDescription == "publicMsg", span.statusCode, span.statusDescription)
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.attrs {
+		attrs[attr.Key] = attr.Value
+	}
+	assert(t, attrs[errsotel.CodeKey].AsString() == "CODE")
+	assert(t, attrs[errsotel.PublicMsgKey].AsString() == "publicMsg")
+	assert(t, attrs[errsotel.UserErrorKey].AsBool() == false)
+	assert(t, attrs["errs.info.UserID"].AsInt64() == 7)
+	assert(t, attrs["errs.info.Name"].AsString() == "Bob")
+	assert(t, attrs["errs.info.Card"].AsString() == "[redacted]", "Expected redacted info")
+
+	span = &recordingSpan{Span: trace.SpanFromContext(context.Background())}
+	errsotel.RecordError(span, nil)
+	assert(t, span.recorded == nil && len(span.attrs) == 0, "Expected nil error to be ignored")
+}
+
+// recordingSpan records the calls made by RecordError
+type recordingSpan struct {
+	trace.Span
+	recorded          error
+	attrs             []attribute.KeyValue
+	statusCode        codes.Code
+	statusDescription string
+}
+
+func (s *recordingSpan) RecordError(err error, opts ...trace.EventOption) { s.recorded = err }
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue)           { s.attrs = append(s.attrs, kv...) }
+func (s *recordingSpan) SetStatus(code codes.Code, description string) {
+	s.statusCode, s.statusDescription = code, description
+}
+
+func assert(t *testing.T, ok bool, msg ...interface{}) {
+	if !ok {
+		panic(msg)
+		// t.Fatal(msg...)
+	}
+}
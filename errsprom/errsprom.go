@@ -0,0 +1,44 @@
+// Package errsprom exports counts of created errs.Errs as Prometheus metrics,
+// labeled by their code and whether they are user errors:
+//
+//	prometheus.MustRegister(errsprom.NewCollector())
+package errsprom
+
+import (
+	"strconv"
+
+	"github.com/marcuswestin/go-errs"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is a prometheus.Collector of the errs_errors_total counter,
+// which counts every errs.Err created, by its "code" and "user_error" labels.
+type Collector struct {
+	counter *prometheus.CounterVec
+}
+
+// NewCollector creates a Collector, and registers an errs.OnError hook
+// which increments its counter. Since hooks cannot be unregistered, a
+// Collector should be created once at startup and then registered.
+func NewCollector() *Collector {
+	c := &Collector{prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "errs_errors_total",
+		Help: "The number of errs.Errs created, by code and user error.",
+	}, []string{"code", "user_error"})}
+	errs.OnError(c.observe)
+	return c
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) { c.counter.Describe(ch) }
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) { c.counter.Collect(ch) }
+
+// Internal
+///////////
+
+// Count the given error
+func (c *Collector) observe(err errs.Err) {
+	c.counter.WithLabelValues(err.Code(), strconv.FormatBool(err.IsUserError())).Inc()
+}
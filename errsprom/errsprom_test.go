@@ -0,0 +1,41 @@
+package errsprom_test
+
+import (
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+	"github.com/marcuswestin/go-errs/errsprom"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCollector(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(errsprom.NewCollector())
+
+	errs.NewWithCode("FIRST", nil)
+	errs.NewWithCode("FIRST", nil)
+	errs.UserError(errs.Info{}).WithInfo("Foo", "Bar")
+	errs.Wrap(errs.NewWithCode("SECOND", nil), nil)
+
+	families, gatherErr := registry.Gather()
+	assert(t, gatherErr == nil, gatherErr)
+	assert(t, len(families) == 1 && families[0].GetName() == "errs_errors_total")
+	counts := map[string]float64{}
+	for _, metric := range families[0].GetMetric() {
+		labels := map[string]string{}
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		counts[labels["code"]+"/"+labels["user_error"]] = metric.GetCounter().GetValue()
+	}
+	assert(t, counts["FIRST/false"] == 2, counts)
+	assert(t, counts["SECOND/false"] == 1, "Expected merging wraps not to be counted again", counts)
+	assert(t, counts["/true"] == 1, counts)
+}
+
+func assert(t *testing.T, ok bool, msg ...interface{}) {
+	if !ok {
+		panic(msg)
+		// t.Fatal(msg...)
+	}
+}
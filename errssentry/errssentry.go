@@ -0,0 +1,114 @@
+// Package errssentry converts errs.Errs into Sentry events, such that
+// identical errors are grouped together by their errs.Fingerprint:
+//
+//	sentry.CaptureEvent(errssentry.ToEvent(err))
+package errssentry
+
+import (
+	"path"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/marcuswestin/go-errs"
+)
+
+// ToEvent converts err into a Sentry event. The stack frames are mapped to
+// the exception's stacktrace, the info to Extra, the code to the "code" tag,
+// and the fingerprint to the event's fingerprint. The info is rendered as
+// for LogString, see errs.Err.LogInfo. If err is nil, ToEvent returns nil.
+func ToEvent(err errs.Err) *sentry.Event {
+	if err == nil {
+		return nil
+	}
+	event := sentry.NewEvent()
+	event.Level = level(err.Level())
+	event.Message = err.PublicMsg()
+	event.Fingerprint = []string{err.Fingerprint()}
+	if code := err.Code(); code != "" {
+		event.Tags["code"] = code
+	}
+	if err.IsUserError() {
+		event.Tags["user_error"] = "true"
+	}
+	for key, val := range err.LogInfo() {
+		event.Extra[key] = val
+	}
+	exceptionType := err.Code()
+	if exceptionType == "" {
+		exceptionType = "errs.Err"
+	}
+	event.Exception = []sentry.Exception{{
+		Type:       exceptionType,
+		Value:      exceptionValue(err),
+		Stacktrace: stacktrace(err.StackFrames()),
+	}}
+	return event
+}
+
+// Internal
+///////////
+
+// Get the Sentry level of the errs level
+func level(level errs.Level) sentry.Level {
+	switch level {
+	case errs.LevelDebug:
+		return sentry.LevelDebug
+	case errs.LevelInfo:
+		return sentry.LevelInfo
+	case errs.LevelWarn:
+		return sentry.LevelWarning
+	case errs.LevelFatal:
+		return sentry.LevelFatal
+	default:
+		return sentry.LevelError
+	}
+}
+
+// Get the exception value, preferring the wrapped error's message
+func exceptionValue(err errs.Err) string {
+	if wrappedErr := err.WrappedError(); wrappedErr != nil {
+		return wrappedErr.Error()
+	}
+	if publicMsg := err.PublicMsg(); publicMsg != "" {
+		return publicMsg
+	}
+	return err.Code()
+}
+
+// Convert the frames into a Sentry stacktrace. Sentry
+// expects the frames ordered from the outermost call.
+func stacktrace(frames []errs.Frame) *sentry.Stacktrace {
+	if len(frames) == 0 {
+		return nil
+	}
+	sentryFrames := make([]sentry.Frame, len(frames))
+	for i, frame := range frames {
+		module, function := splitFunc(frame.Func)
+		sentryFrames[len(frames)-1-i] = sentry.Frame{
+			Function: function,
+			Module:   module,
+			AbsPath:  frame.File,
+			Filename: path.Base(frame.File),
+			Lineno:   frame.Line,
+			InApp:    !isStdlib(module),
+		}
+	}
+	return &sentry.Stacktrace{Frames: sentryFrames}
+}
+
+// Split a fully qualified function name into its package path and function
+// name, e.g "github.com/foo/bar" and "(*Baz).Qux" for "github.com/foo/bar.(*Baz).Qux"
+func splitFunc(funcName string) (string, string) {
+	lastSlash := strings.LastIndex(funcName, "/")
+	if dot := strings.Index(funcName[lastSlash+1:], "."); dot >= 0 {
+		return funcName[:lastSlash+1+dot], funcName[lastSlash+1+dot+1:]
+	}
+	return "", funcName
+}
+
+// Check if the package is in the standard library, i.e
+// if the first element of its path does not contain a dot
+func isStdlib(module string) bool {
+	first, _, _ := strings.Cut(module, "/")
+	return !strings.Contains(first, ".")
+}
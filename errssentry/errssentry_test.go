@@ -0,0 +1,42 @@
+package errssentry_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/marcuswestin/go-errs"
+	"github.com/marcuswestin/go-errs/errssentry"
+)
+
+func TestToEvent(t *testing.T) {
+	err := errs.WrapWithCode("CODE", errors.New("std"), errs.Info{"UserID": 7, "Card": errs.Secret("4111")}, "publicMsg")
+	event := errssentry.ToEvent(err)
+
+	assert(t, len(event.Fingerprint) == 1 && event.Fingerprint[0] == err.Fingerprint(), event.Fingerprint)
+	assert(t, event.Tags["code"] == "CODE", event.Tags)
+	assert(t, event.Extra["UserID"] == 7, event.Extra)
+	assert(t, event.Extra["Card"] == "[redacted]", "Expected redacted info", event.Extra)
+	assert(t, event.Message == "publicMsg")
+	assert(t, event.Level == sentry.LevelError)
+
+	assert(t, len(event.Exception) == 1)
+	exception := event.Exception[0]
+	assert(t, exception.Type == "CODE" && exception.Value == "std", exception.Type, exception.Value)
+	frames := exception.Stacktrace.Frames
+	assert(t, len(frames) > 0, "Expected a non-empty stacktrace")
+	innermost := frames[len(frames)-1]
+	assert(t, innermost.Function == "TestToEvent", "Expected the innermost frame last", innermost.Function)
+	assert(t, innermost.Module == "github.com/marcuswestin/go-errs/errssentry_test")
+	assert(t, innermost.Filename == "errssentry_test.go" && innermost.Lineno > 0 && innermost.InApp)
+
+	assert(t, errssentry.ToEvent(nil) == nil)
+	assert(t, errssentry.ToEvent(errs.UserError(nil)).Level == sentry.LevelWarning)
+}
+
+func assert(t *testing.T, ok bool, msg ...interface{}) {
+	if !ok {
+		panic(msg)
+		// t.Fatal(msg...)
+	}
+}
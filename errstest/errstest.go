@@ -0,0 +1,43 @@
+// Package errstest provides test helpers for code which returns errs.Errs.
+// On failure, the helpers report the error's full LogString, including its
+// stack, so that it is clear where the error originated:
+//
+//	user, err := db.GetUser(id)
+//	errstest.NoError(t, err)
+package errstest
+
+import (
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+// NoError fails the test immediately if err is not nil
+func NoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", logString(err))
+	}
+}
+
+// Code fails the test immediately unless err has the given code,
+// as reported by errs.IsCode
+func Code(t testing.TB, err error, code string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("Expected error with code %q, got nil", code)
+	} else if !errs.IsCode(err, code) {
+		t.Fatalf("Expected error with code %q, got: %s", code, logString(err))
+	}
+}
+
+// Internal
+///////////
+
+// Get the LogString of err if it is an errs.Err, or else its Error string
+func logString(err error) string {
+	if errsErr, isErr := errs.IsErr(err); isErr {
+		return errsErr.LogString()
+	}
+	return err.Error()
+}
@@ -0,0 +1,58 @@
+package errstest_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+	"github.com/marcuswestin/go-errs/errstest"
+)
+
+func TestNoError(t *testing.T) {
+	fake := &fakeTB{}
+	errstest.NoError(fake, nil)
+	assert(t, fake.failure == "", "Expected no failure for nil", fake.failure)
+
+	errstest.NoError(fake, errs.New(errs.Info{"Foo": "Bar"}, "publicMsg"))
+	assert(t, strings.Contains(fake.failure, "Unexpected error"), fake.failure)
+	assert(t, strings.Contains(fake.failure, "Foo=Bar"), "Expected the LogString", fake.failure)
+	assert(t, strings.Contains(fake.failure, "TestNoError"), "Expected the stack", fake.failure)
+	assert(t, fake.numHelpers > 0, "Expected NoError to be marked as a helper")
+}
+
+func TestCode(t *testing.T) {
+	fake := &fakeTB{}
+	errstest.Code(fake, fmt.Errorf("wrapped: %w", errs.NewWithCode("CODE", nil)), "CODE")
+	assert(t, fake.failure == "", "Expected no failure for matching code", fake.failure)
+
+	errstest.Code(fake, errs.NewWithCode("OTHER", nil), "CODE")
+	assert(t, strings.Contains(fake.failure, `Expected error with code "CODE", got: Error`), fake.failure)
+	assert(t, strings.Contains(fake.failure, "Code: OTHER"), fake.failure)
+
+	errstest.Code(fake, errors.New("plain"), "CODE")
+	assert(t, strings.HasSuffix(fake.failure, "got: plain"), fake.failure)
+
+	errstest.Code(fake, nil, "CODE")
+	assert(t, strings.HasSuffix(fake.failure, "got nil"), fake.failure)
+}
+
+// fakeTB records failures rather than failing the test
+type fakeTB struct {
+	testing.TB
+	failure    string
+	numHelpers int
+}
+
+func (f *fakeTB) Helper() { f.numHelpers++ }
+func (f *fakeTB) Fatalf(format string, args ...interface{}) {
+	f.failure = fmt.Sprintf(format, args...)
+}
+
+func assert(t *testing.T, ok bool, msg ...interface{}) {
+	if !ok {
+		panic(msg)
+		// t.Fatal(msg...)
+	}
+}
@@ -0,0 +1,76 @@
+// Package errszap logs errs.Errs as structured zap objects,
+// rather than as a single error string:
+//
+//	logger.Error("Request failed", zap.Object("err", errszap.Wrap(err)))
+package errszap
+
+import (
+	"github.com/marcuswestin/go-errs"
+	"go.uber.org/zap/zapcore"
+)
+
+// Wrap returns a zapcore.ObjectMarshaler for err, which emits its time, code,
+// publicMsg, userError, location and stack fields, and its info as a nested
+// "info" object. The info is rendered as for LogString, see errs.Err.LogInfo.
+// If err is nil, the object is empty.
+func Wrap(err errs.Err) zapcore.ObjectMarshaler {
+	return marshaler{err}
+}
+
+// Internal
+///////////
+
+// marshaler implements zapcore.ObjectMarshaler for an errs.Err
+type marshaler struct {
+	err errs.Err
+}
+
+func (m marshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if m.err == nil {
+		return nil
+	}
+	enc.AddTime("time", m.err.Time())
+	enc.AddString("code", m.err.Code())
+	enc.AddString("publicMsg", m.err.PublicMsg())
+	enc.AddBool("userError", m.err.IsUserError())
+	enc.AddString("location", m.err.Location())
+	if infoErr := enc.AddObject("info", infoMarshaler{m.err}); infoErr != nil {
+		return infoErr
+	}
+	enc.AddString("stack", string(m.err.Stack()))
+	return nil
+}
+
+// infoMarshaler implements zapcore.ObjectMarshaler for the info of an errs.Err
+type infoMarshaler struct {
+	err errs.Err
+}
+
+func (m infoMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	info := m.err.LogInfo()
+	for _, key := range m.err.InfoKeys() {
+		val, isLogged := info[key]
+		if !isLogged {
+			continue
+		}
+		switch val := val.(type) {
+		case string:
+			enc.AddString(key, val)
+		case bool:
+			enc.AddBool(key, val)
+		case int:
+			enc.AddInt(key, val)
+		case int64:
+			enc.AddInt64(key, val)
+		case float64:
+			enc.AddFloat64(key, val)
+		case error:
+			enc.AddString(key, val.Error())
+		default:
+			if reflectErr := enc.AddReflected(key, val); reflectErr != nil {
+				return reflectErr
+			}
+		}
+	}
+	return nil
+}
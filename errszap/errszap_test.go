@@ -0,0 +1,45 @@
+package errszap_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+	"github.com/marcuswestin/go-errs/errszap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWrap(t *testing.T) {
+	err := errs.WrapWithCode("CODE", errors.New("std"), errs.Info{"Foo": "Bar", "Num": 7, "Card": errs.Secret("4111")}, "publicMsg")
+	enc := zapcore.NewMapObjectEncoder()
+	assert(t, errszap.Wrap(err).MarshalLogObject(enc) == nil, "Expected marshal to succeed")
+
+	assert(t, enc.Fields["code"] == "CODE" && enc.Fields["publicMsg"] == "publicMsg", enc.Fields)
+	assert(t, enc.Fields["userError"] == false)
+	assert(t, strings.Contains(enc.Fields["stack"].(string), "TestWrap"), "Expected the stack")
+	info := enc.Fields["info"].(map[string]interface{})
+	assert(t, info["Foo"] == "Bar" && info["Num"] == 7, "Expected nested info", info)
+	assert(t, info["Card"] == "[redacted]", "Expected redacted info", info)
+
+	enc = zapcore.NewMapObjectEncoder()
+	assert(t, errszap.Wrap(nil).MarshalLogObject(enc) == nil && len(enc.Fields) == 0)
+}
+
+func TestWrapInfoLogFilter(t *testing.T) {
+	errs.SetInfoLogFilter(func(key string, val interface{}) bool { return !strings.HasPrefix(key, "pii_") })
+	defer errs.SetInfoLogFilter(nil)
+	err := errs.New(errs.Info{"pii_email": "jane@example.com", "Plan": "pro"})
+	enc := zapcore.NewMapObjectEncoder()
+	assert(t, errszap.Wrap(err).MarshalLogObject(enc) == nil, "Expected marshal to succeed")
+	info := enc.Fields["info"].(map[string]interface{})
+	_, hasEmail := info["pii_email"]
+	assert(t, !hasEmail && info["Plan"] == "pro", "Expected filtered info to be left out", info)
+}
+
+func assert(t *testing.T, ok bool, msg ...interface{}) {
+	if !ok {
+		panic(msg)
+		// t.Fatal(msg...)
+	}
+}
@@ -0,0 +1,36 @@
+package errs
+
+import (
+	"fmt"
+	"io"
+)
+
+// Format implements fmt.Formatter. The %v, %s and %q verbs print a
+// single-line summary of the error, without its stack. The %+v verb
+// prints the summary followed by each frame of the stack on its own line:
+//
+//	fmt.Printf("%v", err)  // Error | Time: ... | PublicMsg: ...
+//	fmt.Printf("%+v", err) // Error | Time: ... | PublicMsg: ...\nStack:\n...
+func (e *err) Format(s fmt.State, verb rune) {
+	formatErr(s, verb, e.summary(), e.cachedStack())
+}
+
+// Internal
+///////////
+
+func formatErr(s fmt.State, verb rune, summary string, stack []byte) {
+	switch verb {
+	case 'v':
+		io.WriteString(s, summary)
+		if s.Flag('+') && stack != nil {
+			io.WriteString(s, "\nStack:\n")
+			s.Write(stack)
+		}
+	case 's':
+		io.WriteString(s, summary)
+	case 'q':
+		fmt.Fprintf(s, "%q", summary)
+	default:
+		fmt.Fprintf(s, "%%!%c(errs.Err=%s)", verb, summary)
+	}
+}
@@ -0,0 +1,33 @@
+package errs_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestFormat(t *testing.T) {
+	err := errs.New(errs.Info{"Foo": "Bar"}, "publicMsg")
+	for _, verb := range []string{"%v", "%s"} {
+		str := fmt.Sprintf(verb, err)
+		assert(t, strings.Contains(str, "publicMsg"), "Expected public message in", verb)
+		assert(t, strings.Contains(str, "Foo=Bar"), "Expected info in", verb)
+		assert(t, !strings.Contains(str, ".TestFormat"), "Expected no stack in", verb)
+		assert(t, !strings.Contains(str, "\n"), "Expected a single line for", verb)
+	}
+	str := fmt.Sprintf("%+v", err)
+	assert(t, strings.Contains(str, "publicMsg"), "Expected public message in %+v")
+	assert(t, strings.Contains(str, "Foo=Bar"), "Expected info in %+v")
+	assert(t, strings.Contains(str, ".TestFormat()\n\t"), "Expected stack frames in %+v")
+	assert(t, strings.Contains(str, "format_test.go:"), "Expected stack frame file in %+v")
+	assert(t, fmt.Sprintf("%q", err) == fmt.Sprintf("%q", fmt.Sprintf("%v", err)))
+}
+
+func TestFormatJoin(t *testing.T) {
+	err := errs.Join(errs.New(nil, "First"), errs.New(nil, "Second"))
+	str := fmt.Sprintf("%v", err)
+	assert(t, strings.Contains(str, "First") && strings.Contains(str, "Second"))
+	assert(t, !strings.Contains(str, "\n"), "Expected a single line for %v")
+}
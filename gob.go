@@ -0,0 +1,111 @@
+package errs
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"time"
+)
+
+func init() {
+	// Allow errs.Errs to be gob encoded as interface values,
+	// e.g as the error field of a net/rpc reply struct
+	gob.Register(&err{})
+	gob.Register(&ValidationErr{})
+	gob.Register(&joinedErr{})
+}
+
+// GobEncode implements gob.GobEncoder, so that errors survive RPC boundaries.
+// Info values of basic types like strings and numbers are encoded as is,
+// while other values are encoded with their %v string representations.
+// Values are not truncated or redacted, since they are not being logged.
+// The wrapped error is encoded as its error string.
+func (e *err) GobEncode() ([]byte, error) {
+	v := e.view()
+	stack := v.cachedStack()
+	v.mu.RLock()
+	gobE := gobErr{
+		Time:         v.time,
+		GoroutineID:  v.goroutineID,
+		Code:         v.code,
+		PublicMsg:    v.publicMsg,
+		Info:         gobInfo(v.info),
+		WrappedError: v.wrappedErrStr(),
+		IsUserError:  v.isUserErr,
+		HTTPStatus:   v.httpStatus,
+		Retryable:    v.retryable,
+		RetryableSet: v.retryableSet,
+		Level:        v.level,
+		Stack:        stack,
+		AllStacks:    v.allStacks,
+	}
+	v.mu.RUnlock()
+	var buf bytes.Buffer
+	if encodeErr := gob.NewEncoder(&buf).Encode(gobE); encodeErr != nil {
+		return nil, encodeErr
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder. The decoded error has no program
+// counters, so StackFrames returns nil, but Stack returns the decoded stack.
+func (e *err) GobDecode(data []byte) error {
+	var gobE gobErr
+	if decodeErr := gob.NewDecoder(bytes.NewReader(data)).Decode(&gobE); decodeErr != nil {
+		return decodeErr
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.time = gobE.Time
+	e.goroutineID = gobE.GoroutineID
+	e.code = gobE.Code
+	e.publicMsg = gobE.PublicMsg
+	e.info = gobE.Info
+	if gobE.WrappedError != "" {
+		e.wrappedErr = errors.New(gobE.WrappedError)
+	}
+	e.isUserErr = gobE.IsUserError
+	e.httpStatus = gobE.HTTPStatus
+	e.retryable, e.retryableSet = gobE.Retryable, gobE.RetryableSet
+	e.level = gobE.Level
+	e.allStacks = gobE.AllStacks
+	e.stackOnce.Do(func() { e.stack = gobE.Stack })
+	return nil
+}
+
+// Internal
+///////////
+
+// gobErr is the gob representation of an err
+type gobErr struct {
+	Time         time.Time
+	GoroutineID  uint64
+	Code         string
+	PublicMsg    []string
+	Info         map[string]interface{}
+	WrappedError string
+	IsUserError  bool
+	HTTPStatus   int
+	Retryable    bool
+	RetryableSet bool
+	Level        Level
+	Stack        []byte
+	AllStacks    []byte
+}
+
+// Get a copy of info in which all values that are not of
+// a basic type are replaced with their %v string representations
+func gobInfo(info Info) map[string]interface{} {
+	res := make(map[string]interface{}, len(info))
+	for key, val := range info {
+		switch val.(type) {
+		case nil, string, bool, int, int8, int16, int32, int64,
+			uint, uint8, uint16, uint32, uint64, float32, float64:
+			res[key] = val
+		default:
+			res[key] = fmt.Sprintf("%v", val)
+		}
+	}
+	return res
+}
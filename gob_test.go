@@ -0,0 +1,64 @@
+package errs_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestGob(t *testing.T) {
+	type reply struct{ Err errs.Err }
+	orig := errs.WrapWithCode("CODE", errors.New("std"), errs.Info{"Str": "Bar", "Num": 7, "Struct": struct{ A int }{1}}, "publicMsg")
+	orig.WithRetryable(true)
+
+	var buf bytes.Buffer
+	assert(t, gob.NewEncoder(&buf).Encode(reply{orig}) == nil, "Expected encode to succeed")
+	var res reply
+	decodeErr := gob.NewDecoder(&buf).Decode(&res)
+	assert(t, decodeErr == nil, "Expected decode to succeed", decodeErr)
+
+	err := res.Err
+	assert(t, err.PublicMsg() == "publicMsg", err.PublicMsg())
+	assert(t, err.Code() == "CODE")
+	assert(t, err.Info("Str") == "Bar" && err.Info("Num") == 7, err.AllInfo())
+	assert(t, err.Info("Struct") == "{1}", "Expected non-basic value to be stringified", err.Info("Struct"))
+	assert(t, err.WrappedError().Error() == "std")
+	assert(t, err.Retryable())
+	assert(t, err.Time().Equal(orig.Time()))
+	assert(t, string(err.Stack()) == string(orig.Stack()), "Expected stack to survive", string(err.Stack()))
+	assert(t, err.LogString() != "", "Expected a functioning Err")
+}
+
+func TestGobJoin(t *testing.T) {
+	type reply struct{ Err error }
+	orig := errs.Join(errs.UserError(nil, "Email is taken"), errors.New("std"))
+
+	var buf bytes.Buffer
+	encodeErr := gob.NewEncoder(&buf).Encode(reply{orig})
+	assert(t, encodeErr == nil, "Expected encode to succeed", encodeErr)
+	var res reply
+	decodeErr := gob.NewDecoder(&buf).Decode(&res)
+	assert(t, decodeErr == nil, "Expected decode to succeed", decodeErr)
+
+	err, isErr := res.Err.(errs.Err)
+	assert(t, isErr && err.PublicMsg() == "Email is taken", res.Err)
+	children := res.Err.(interface{ Unwrap() []error }).Unwrap()
+	assert(t, len(children) == 2, "Expected the joined errors to survive", children)
+	child, isErr := children[0].(errs.Err)
+	assert(t, isErr && child.IsUserError() && child.PublicMsg() == "Email is taken", children[0])
+	assert(t, children[1].Error() == "std", children[1])
+}
+
+func TestGobInfoNotTruncated(t *testing.T) {
+	errs.SetMaxInfoValueLen(3)
+	defer errs.SetMaxInfoValueLen(0)
+	type reply struct{ Err errs.Err }
+	var buf bytes.Buffer
+	assert(t, gob.NewEncoder(&buf).Encode(reply{errs.New(errs.Info{"SQL": "SELECT 1"}, "Query failed")}) == nil)
+	var res reply
+	assert(t, gob.NewDecoder(&buf).Decode(&res) == nil)
+	assert(t, res.Err.Info("SQL") == "SELECT 1", "Expected info not to be truncated", res.Err.Info("SQL"))
+}
@@ -0,0 +1,45 @@
+package errs
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// SetCaptureGoroutineID controls whether errors capture the ID of the
+// goroutine which created them, for diagnosing concurrency bugs. See
+// Err.GoroutineID. Goroutine IDs are parsed from runtime.Stack, which
+// adds roughly a microsecond to the creation of every error, so capture
+// is opt-in. The default is false.
+func SetCaptureGoroutineID(capture bool) {
+	captureGoroutineID.Store(capture)
+}
+
+// Implements Err
+func (e *err) GoroutineID() uint64 { return e.goroutineID }
+
+// Internal
+///////////
+
+var captureGoroutineID atomic.Bool
+
+// Get the ID of the current goroutine if capture is enabled, or else 0
+func goroutineID() uint64 {
+	if !captureGoroutineID.Load() {
+		return 0
+	}
+	// The first line of the stack is e.g "goroutine 18 [running]:"
+	var buf [64]byte
+	stack := buf[:runtime.Stack(buf[:], false)]
+	const prefix = "goroutine "
+	if len(stack) < len(prefix) {
+		return 0
+	}
+	var id uint64
+	for _, c := range stack[len(prefix):] {
+		if c < '0' || c > '9' {
+			break
+		}
+		id = id*10 + uint64(c-'0')
+	}
+	return id
+}
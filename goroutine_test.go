@@ -0,0 +1,30 @@
+package errs_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestGoroutineID(t *testing.T) {
+	assert(t, errs.New(nil).GoroutineID() == 0, "Expected no goroutine ID by default")
+	assert(t, !strings.Contains(errs.New(nil).LogString(), "Goroutine:"))
+
+	errs.SetCaptureGoroutineID(true)
+	defer errs.SetCaptureGoroutineID(false)
+	err := errs.New(nil)
+	otherErr := make(chan errs.Err)
+	go func() { otherErr <- errs.New(nil) }()
+	other := <-otherErr
+	assert(t, err.GoroutineID() != 0 && other.GoroutineID() != 0, "Expected captured goroutine IDs")
+	assert(t, err.GoroutineID() != other.GoroutineID(), "Expected different goroutine IDs", err.GoroutineID())
+	assert(t, errs.New(nil).GoroutineID() == err.GoroutineID(), "Expected the same ID on the same goroutine")
+
+	id := strconv.FormatUint(err.GoroutineID(), 10)
+	assert(t, strings.Contains(err.LogString(), "| Goroutine: "+id+" | Code:"), err.LogString())
+	data, _ := json.Marshal(err)
+	assert(t, strings.Contains(string(data), `"goroutineID":`+id), string(data))
+}
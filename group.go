@@ -0,0 +1,48 @@
+package errs
+
+import "sync"
+
+// Group runs functions concurrently and collects all of their errors.
+// Unlike golang.org/x/sync/errgroup, no errors are discarded: Wait returns
+// every failure joined with errs.Join, with each error's own stack intact.
+// The zero Group is ready to use, e.g
+//
+//	var group errs.Group
+//	for _, url := range urls {
+//		group.Go(func() error { return fetch(url) })
+//	}
+//	if err := group.Wait(); err != nil { ... }
+type Group struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// Go calls fn in a new goroutine. Its error, if any,
+// is returned by Wait together with all other errors.
+func (g *Group) Go(fn func() error) {
+	g.mu.Lock()
+	index := len(g.errs)
+	g.errs = append(g.errs, nil)
+	g.mu.Unlock()
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := fn(); err != nil {
+			g.mu.Lock()
+			g.errs[index] = err
+			g.mu.Unlock()
+		}
+	}()
+}
+
+// Wait blocks until all functions passed to Go have returned, and then
+// returns their non-nil errors joined with errs.Join, in the order in
+// which the functions were passed to Go. If there were no errors then
+// Wait returns nil.
+func (g *Group) Wait() Err {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return Join(g.errs...)
+}
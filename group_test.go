@@ -0,0 +1,44 @@
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestGroup(t *testing.T) {
+	var group errs.Group
+	for i := 0; i < 5; i++ {
+		group.Go(func() error {
+			if i%2 == 1 {
+				return nil
+			}
+			return failInGroup(i)
+		})
+	}
+	err := group.Wait()
+	assert(t, err != nil, "Expected errors")
+	children := err.(interface{ Unwrap() []error }).Unwrap()
+	assert(t, len(children) == 3, "Expected all three errors", len(children))
+	for i, child := range children {
+		childErr, isErr := errs.IsErr(child)
+		assert(t, isErr && childErr.PublicMsg() == fmt.Sprint("failed ", i*2), child)
+		assert(t, strings.Contains(string(childErr.Stack()), "failInGroup"), "Expected each error's own stack")
+	}
+
+	var empty errs.Group
+	empty.Go(func() error { return nil })
+	assert(t, empty.Wait() == nil, "Expected nil without errors")
+
+	var plain errs.Group
+	target := errors.New("target")
+	plain.Go(func() error { return target })
+	assert(t, errors.Is(plain.Wait(), target), "Expected plain errors to be kept")
+}
+
+func failInGroup(i int) error {
+	return errs.New(nil, "failed", i)
+}
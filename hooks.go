@@ -0,0 +1,83 @@
+package errs
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// OnError registers a hook which is called with every errs.Err created
+// by e.g errs.New or errs.Wrap, right before it is returned. This is useful
+// for centralized reporting, e.g incrementing a metric or sending the error
+// to a monitoring service. Hooks are called in registration order. Errors
+// created from within a hook do not trigger the hooks again.
+func OnError(hook func(Err)) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	var hooks []func(Err)
+	if current := errorHooks.Load(); current != nil {
+		hooks = append(hooks, *current...)
+	}
+	hooks = append(hooks, hook)
+	errorHooks.Store(&hooks)
+}
+
+// OnErrorLevel registers a hook like OnError, which is only called with errors
+// whose Level is at least the given level, e.g `errs.OnErrorLevel(errs.LevelFatal, page)`
+// is called for fatal errors, while a hook registered with errs.LevelWarn is
+// called for warnings and all more severe errors. The level is checked when
+// the error is created, so levels set later with Err.WithLevel are not seen.
+func OnErrorLevel(level Level, hook func(Err)) {
+	OnError(func(err Err) {
+		if err.Level() >= level {
+			hook(err)
+		}
+	})
+}
+
+// Internal
+///////////
+
+var (
+	hooksMu    sync.Mutex
+	errorHooks atomic.Pointer[[]func(Err)]
+	// The number of goroutines which are currently calling hooks
+	numReporting atomic.Int64
+)
+
+// The function name of reportErr, for detecting reentrant calls
+var reportErrFuncName = errsFuncPrefix + "reportErr"
+
+// Pass the given error to all hooks registered with OnError,
+// unless the current goroutine is already doing so
+func reportErr(e Err) {
+	hooks := errorHooks.Load()
+	if hooks == nil {
+		return
+	}
+	if numReporting.Load() > 0 && isReporting() {
+		return
+	}
+	numReporting.Add(1)
+	defer numReporting.Add(-1)
+	for _, hook := range *hooks {
+		hook(e)
+	}
+}
+
+// Check if reportErr is on the current goroutine's stack,
+// skipping the frames of isReporting and its caller
+func isReporting() bool {
+	pcs := make([]uintptr, 32)
+	for skip := 3; ; skip += len(pcs) {
+		n := runtime.Callers(skip, pcs)
+		for _, pc := range pcs[:n] {
+			if fn := runtime.FuncForPC(pc - 1); fn != nil && fn.Name() == reportErrFuncName {
+				return true
+			}
+		}
+		if n < len(pcs) {
+			return false
+		}
+	}
+}
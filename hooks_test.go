@@ -0,0 +1,92 @@
+package errs_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+// Hooks cannot be unregistered, so tests register a single hook
+// which forwards to the current test's hook.
+var testHook func(errs.Err)
+
+func init() {
+	errs.OnError(func(err errs.Err) {
+		if testHook != nil {
+			testHook(err)
+		}
+	})
+}
+
+func TestOnError(t *testing.T) {
+	var reported []errs.Err
+	var order []int
+	testHook = func(err errs.Err) {
+		reported = append(reported, err)
+		order = append(order, 1)
+		errs.New(nil, "Created inside a hook")
+	}
+	errs.OnError(func(err errs.Err) {
+		if testHook != nil {
+			order = append(order, 2)
+		}
+	})
+	defer func() { testHook = nil }()
+
+	err := errs.NewWithCode("CODE", nil)
+	assert(t, len(reported) == 1, "Expected hook to fire once per errs.New", len(reported))
+	assert(t, reported[0] == err)
+	assert(t, reported[0].Code() == "CODE", "Expected the hook to see the fully built error")
+	assert(t, len(order) == 2 && order[0] == 1 && order[1] == 2, "Expected hooks to be called in registration order", order)
+
+	errs.Wrap(err, nil)
+	assert(t, len(reported) == 1, "Expected hook to not fire when merging into an existing error")
+	errs.Join(err, err)
+	assert(t, len(reported) == 2, "Expected hook to fire for errs.Join")
+}
+
+func TestOnErrorConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	numReported := 0
+	testHook = func(err errs.Err) {
+		mu.Lock()
+		numReported++
+		mu.Unlock()
+		errs.New(nil, "Created inside a hook")
+	}
+	defer func() { testHook = nil }()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs.New(nil)
+		}()
+	}
+	wg.Wait()
+	assert(t, numReported == 20, "Expected hook to fire once per error across goroutines", numReported)
+}
+
+func TestOnErrorLevel(t *testing.T) {
+	var warnings, fatals []errs.Err
+	testHook = func(err errs.Err) {}
+	defer func() { testHook = nil }()
+	errs.OnErrorLevel(errs.LevelWarn, func(err errs.Err) {
+		if testHook != nil {
+			warnings = append(warnings, err)
+		}
+	})
+	errs.OnErrorLevel(errs.LevelFatal, func(err errs.Err) {
+		if testHook != nil {
+			fatals = append(fatals, err)
+		}
+	})
+
+	errs.NewLevel(errs.LevelInfo, nil)
+	assert(t, len(warnings) == 0 && len(fatals) == 0, "Expected no hooks to fire below their levels")
+	errs.NewLevel(errs.LevelWarn, nil)
+	assert(t, len(warnings) == 1 && len(fatals) == 0, "Expected only the warning hook to fire", len(warnings), len(fatals))
+	fatal := errs.NewLevel(errs.LevelFatal, nil)
+	assert(t, len(warnings) == 2 && len(fatals) == 1 && fatals[0] == fatal, "Expected both hooks to fire for a fatal error", len(warnings), len(fatals))
+}
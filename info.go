@@ -0,0 +1,263 @@
+package errs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// The canonical info keys for values which are commonly attached to errors.
+// Use these rather than ad-hoc spellings like "ReqID" or "request_id", so
+// that logs can be searched and aggregated consistently by key,
+// e.g `errs.Wrap(err, errs.Info{errs.KeyUserID: userID})`
+const (
+	KeyRequestID = "RequestID"
+	KeyUserID    = "UserID"
+	KeyTraceID   = "TraceID"
+)
+
+// Redactor is implemented by info values which must never be logged in
+// cleartext. When rendering info in LogString, MarshalJSON and LogValue,
+// the result of Redact is used instead of the value.
+type Redactor interface {
+	Redact() string
+}
+
+// Secret wraps an info value so that it is rendered as "[redacted]" in logs,
+// e.g `errs.New(errs.Info{"CardNumber": errs.Secret(cardNumber)})`.
+// Err.Info still returns the underlying value for internal use, while
+// Err.AllInfo returns the wrapped value.
+func Secret(val interface{}) Redactor {
+	return secret{val}
+}
+
+// SetMaxInfoValueLen limits the length of info values when they are rendered
+// in LogString, MarshalJSON and LogValue. Longer values are truncated, with
+// a "…(truncated)" suffix. Err.Info still returns the full value for
+// in-process use. The default, 0, means unlimited.
+func SetMaxInfoValueLen(n int) {
+	maxInfoValueLen.Store(int64(n))
+}
+
+// SetInfoLogFilter sets a filter which is consulted for each info key-value-pair
+// when rendering info in LogString, MarshalJSON, LogValue and AllFields. Pairs
+// for which it returns false are left out, e.g to keep personal data out of logs.
+// Unlike Redactor, which masks values, this drops the pairs entirely. Err.Info
+// still returns the values for in-process use. Passing nil removes the filter.
+func SetInfoLogFilter(filter func(key string, val interface{}) bool) {
+	if filter == nil {
+		infoLogFilter.Store(nil)
+		return
+	}
+	infoLogFilter.Store(&filter)
+}
+
+// String renders the info as space-separated key=value pairs in sorted key
+// order, e.g "a=1 b=2". Values are redacted and truncated like in LogString.
+func (info Info) String() string {
+	var buf strings.Builder
+	for i, key := range sortedKeys(info) {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		fmt.Fprintf(&buf, "%s=%v", key, renderValue(info[key]))
+	}
+	return buf.String()
+}
+
+// Implements Err
+func (e *err) HasInfo(name string) bool {
+	if e.chained {
+		return e.view().HasInfo(name)
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	_, hasKey := e.info[name]
+	return hasKey
+}
+
+// Implements Err
+func (e *err) InfoKeys() []string {
+	if e.chained {
+		return e.view().InfoKeys()
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return sortedKeys(e.info)
+}
+
+// Implements Err
+func (e *err) InfoAll(key string) []interface{} {
+	info := e.AllInfo()
+	var vals []interface{}
+	for ; ; key += duplicateSuffix {
+		val, hasKey := info[key]
+		if !hasKey {
+			return vals
+		}
+		vals = append(vals, unwrapSecret(val))
+	}
+}
+
+// Implements Err
+func (e *err) LogInfo() Info {
+	info := filterInfo(e.AllInfo())
+	for key, val := range info {
+		info[key] = renderValue(val)
+	}
+	return info
+}
+
+// Implements Err
+func (e *err) AllFields() map[string]interface{} {
+	return e.allFields(e.PublicMsg())
+}
+
+// Implements Err
+func (e *err) InfoString(name string) (string, bool) {
+	str, isStr := e.Info(name).(string)
+	return str, isStr
+}
+
+// Implements Err
+func (e *err) InfoInt(name string) (int64, bool) {
+	switch val := e.Info(name).(type) {
+	case int:
+		return int64(val), true
+	case int8:
+		return int64(val), true
+	case int16:
+		return int64(val), true
+	case int32:
+		return int64(val), true
+	case int64:
+		return val, true
+	case uint:
+		return int64(val), true
+	case uint8:
+		return int64(val), true
+	case uint16:
+		return int64(val), true
+	case uint32:
+		return int64(val), true
+	case uint64:
+		return int64(val), true
+	case float32:
+		return int64(val), true
+	case float64:
+		return int64(val), true
+	default:
+		return 0, false
+	}
+}
+
+// Implements Err
+func (e *err) InfoBool(name string) (bool, bool) {
+	b, isBool := e.Info(name).(bool)
+	return b, isBool
+}
+
+// Internal
+///////////
+
+var maxInfoValueLen atomic.Int64
+
+// The filter set with SetInfoLogFilter, or nil for none
+var infoLogFilter atomic.Pointer[func(key string, val interface{}) bool]
+
+// The suffix of info values truncated by SetMaxInfoValueLen
+const truncatedSuffix = "…(truncated)"
+
+// Get the AllFields of the error with the given public message
+func (e *err) allFields(publicMsg string) map[string]interface{} {
+	info := e.LogInfo()
+	fields := make(map[string]interface{}, len(info)+5)
+	fields["publicMsg"] = publicMsg
+	fields["code"] = e.Code()
+	fields["userError"] = e.IsUserError()
+	fields["time"] = e.Time()
+	fields["location"] = e.Location()
+	var collisions Info
+	for key, val := range info {
+		if _, isReserved := fields[key]; isReserved || key == "info" {
+			if collisions == nil {
+				collisions = Info{}
+			}
+			collisions[key] = val
+		} else {
+			fields[key] = val
+		}
+	}
+	if collisions != nil {
+		fields["info"] = collisions
+	}
+	return fields
+}
+
+// Get the keys of info in sorted order
+func sortedKeys(info Info) []string {
+	keys := make([]string, 0, len(info))
+	for key := range info {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// secret implements Redactor for Secret
+type secret struct {
+	val interface{}
+}
+
+func (s secret) Redact() string { return "[redacted]" }
+func (s secret) String() string { return s.Redact() }
+
+// Get the underlying value of val if it is a Secret
+func unwrapSecret(val interface{}) interface{} {
+	if s, isSecret := val.(secret); isSecret {
+		return s.val
+	}
+	return val
+}
+
+// Get the value to render for the given info value, which is
+// redacted and truncated according to SetMaxInfoValueLen
+func renderValue(val interface{}) interface{} {
+	if redactor, isRedactor := val.(Redactor); isRedactor {
+		val = redactor.Redact()
+	}
+	maxLen := int(maxInfoValueLen.Load())
+	if maxLen <= 0 {
+		return val
+	}
+	str, isStr := val.(string)
+	if !isStr {
+		str = fmt.Sprintf("%v", val)
+	}
+	if len(str) <= maxLen {
+		return val
+	}
+	// Don't cut a multi-byte character in half
+	for maxLen > 0 && !utf8.RuneStart(str[maxLen]) {
+		maxLen--
+	}
+	return str[:maxLen] + truncatedSuffix
+}
+
+// Get info without the pairs dropped by the SetInfoLogFilter filter.
+// Info is returned as is if there is no filter.
+func filterInfo(info Info) Info {
+	filter := infoLogFilter.Load()
+	if filter == nil {
+		return info
+	}
+	filtered := make(Info, len(info))
+	for key, val := range info {
+		if (*filter)(key, val) {
+			filtered[key] = val
+		}
+	}
+	return filtered
+}
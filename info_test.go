@@ -0,0 +1,261 @@
+package errs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestTypedInfo(t *testing.T) {
+	err := errs.New(errs.Info{"Str": "Bar", "Int": 7, "Int32": int32(8), "Float": 9.0, "Bool": true})
+
+	str, ok := err.InfoString("Str")
+	assert(t, ok && str == "Bar")
+	_, ok = err.InfoString("Int")
+	assert(t, !ok, "Expected InfoString of an int to fail")
+	_, ok = err.InfoString("Missing")
+	assert(t, !ok, "Expected InfoString of a missing key to fail")
+
+	num, ok := err.InfoInt("Int")
+	assert(t, ok && num == 7)
+	num, ok = err.InfoInt("Int32")
+	assert(t, ok && num == 8)
+	num, ok = err.InfoInt("Float")
+	assert(t, ok && num == 9)
+	_, ok = err.InfoInt("Str")
+	assert(t, !ok, "Expected InfoInt of a string to fail")
+	_, ok = err.InfoInt("Missing")
+	assert(t, !ok, "Expected InfoInt of a missing key to fail")
+
+	b, ok := err.InfoBool("Bool")
+	assert(t, ok && b)
+	_, ok = err.InfoBool("Str")
+	assert(t, !ok, "Expected InfoBool of a string to fail")
+}
+
+func TestSortedInfo(t *testing.T) {
+	err := errs.New(errs.Info{"b": 2, "a": 1, "c": 3})
+	for i := 0; i < 10; i++ {
+		assert(t, strings.Contains(err.LogString(), "| Info:[a=1 b=2 c=3]"), "Expected sorted info", err.LogString())
+	}
+}
+
+func TestInfoString(t *testing.T) {
+	assert(t, errs.Info{"b": 2, "a": 1}.String() == "a=1 b=2", errs.Info{"b": 2, "a": 1}.String())
+	assert(t, errs.Info{"Card": errs.Secret("4111")}.String() == "Card=[redacted]")
+	assert(t, errs.Info{}.String() == "" && errs.Info(nil).String() == "")
+}
+
+func TestSecret(t *testing.T) {
+	err := errs.New(errs.Info{"CardNumber": errs.Secret("4111111111111111")})
+	assert(t, strings.Contains(err.LogString(), "CardNumber=[redacted]"), "Expected redacted info in LogString")
+	assert(t, !strings.Contains(err.LogString(), "4111111111111111"), "Expected no cleartext secret in LogString")
+	data, _ := json.Marshal(err)
+	assert(t, !strings.Contains(string(data), "4111111111111111"), "Expected no cleartext secret in JSON")
+	assert(t, err.Info("CardNumber") == "4111111111111111", "Expected Info to return the underlying value")
+	cardNumber, _ := err.InfoString("CardNumber")
+	assert(t, cardNumber == "4111111111111111")
+}
+
+func TestInfoLogFilter(t *testing.T) {
+	errs.SetInfoLogFilter(func(key string, val interface{}) bool { return !strings.HasPrefix(key, "pii_") })
+	defer errs.SetInfoLogFilter(nil)
+	err := errs.New(errs.Info{"pii_email": "jane@example.com", "pii_name": "Jane", "Plan": "pro"})
+	assert(t, !strings.Contains(err.LogString(), "pii_"), "Expected no filtered keys in LogString", err.LogString())
+	assert(t, strings.Contains(err.LogString(), "Plan=pro"), "Expected unfiltered keys in LogString", err.LogString())
+	data, _ := json.Marshal(err)
+	assert(t, !strings.Contains(string(data), "jane@example.com"), "Expected no filtered values in JSON", string(data))
+	assert(t, err.Info("pii_email") == "jane@example.com", "Expected Info to return filtered values")
+}
+
+func TestLogInfo(t *testing.T) {
+	errs.SetInfoLogFilter(func(key string, val interface{}) bool { return !strings.HasPrefix(key, "pii_") })
+	defer errs.SetInfoLogFilter(nil)
+	errs.SetMaxInfoValueLen(10)
+	defer errs.SetMaxInfoValueLen(0)
+	err := errs.New(errs.Info{"pii_email": "jane@example.com", "Card": errs.Secret("4111"), "SQL": "SELECT * FROM users", "Num": 7})
+	info := err.LogInfo()
+	_, hasEmail := info["pii_email"]
+	assert(t, !hasEmail, "Expected filtered keys to be left out", info)
+	assert(t, info["Card"] == "[redacted]" && info["SQL"] == "SELECT * F…(truncated)" && info["Num"] == 7, info)
+	assert(t, err.Info("SQL") == "SELECT * FROM users", "Expected the info to be unchanged")
+}
+
+func TestWithInfo(t *testing.T) {
+	err := errs.New(errs.Info{"Foo": "Bar"})
+	stack, errTime := string(err.Stack()), err.Time()
+	assert(t, err.WithInfo("Cat", "Mat") == err, "Expected WithInfo to return the same error")
+	assert(t, err.Info("Cat") == "Mat")
+	err.WithInfo("Foo", "Baz")
+	assert(t, err.Info("Foo") == "Bar")
+	assert(t, err.Info("Foo_duplicate") == "Baz", "Expected WithInfo to rename duplicate keys")
+	err.WithInfoMap(errs.Info{"A": 1, "B": 2})
+	assert(t, err.Info("A") == 1 && err.Info("B") == 2)
+	assert(t, string(err.Stack()) == stack, "Expected the stack to be unchanged")
+	assert(t, err.Time().Equal(errTime), "Expected the time to be unchanged")
+
+	joined := errs.Join(err)
+	assert(t, joined.WithInfo("Joined", true) == joined, "Expected WithInfo on a joined error to return it")
+	assert(t, joined.Info("Joined") == true)
+}
+
+func TestMergePolicy(t *testing.T) {
+	defer errs.SetMergePolicy(errs.MergeSuffix)
+	wrapThrice := func() errs.Err {
+		err := errs.New(errs.Info{"Key": "First"})
+		err = errs.Wrap(err, errs.Info{"Key": "Second"})
+		return errs.Wrap(err, errs.Info{"Key": "Third"})
+	}
+
+	errs.SetMergePolicy(errs.MergeSuffix)
+	err := wrapThrice()
+	assert(t, err.Info("Key") == "First")
+	assert(t, err.Info("Key_duplicate_duplicate") == "Third")
+
+	errs.SetMergePolicy(errs.MergeOverwrite)
+	err = wrapThrice()
+	assert(t, err.Info("Key") == "Third", "Expected MergeOverwrite to keep the newest value")
+	assert(t, len(err.AllInfo()) == 1)
+
+	errs.SetMergePolicy(errs.MergeKeepFirst)
+	err = wrapThrice()
+	assert(t, err.Info("Key") == "First", "Expected MergeKeepFirst to keep the original value")
+	assert(t, len(err.AllInfo()) == 1)
+}
+
+func TestMergeReplacePublic(t *testing.T) {
+	defer errs.SetMergePolicy(errs.MergeSuffix)
+	errs.SetMergePolicy(errs.MergeOverwrite | errs.MergeReplacePublic)
+	err := errs.New(errs.Info{"Key": "First"}, "Inner")
+	err = errs.Wrap(err, errs.Info{"Key": "Second"}, "Middle")
+	err = errs.Wrap(err, nil)
+	err = errs.Wrap(err, nil, "Outer")
+	assert(t, err.PublicMsg() == "Outer", "Expected only the outermost public message", err.PublicMsg())
+	assert(t, err.Info("Key") == "Second", "Expected the info policy to still apply", err.Info("Key"))
+
+	errs.SetPreserveChain(true)
+	defer errs.SetPreserveChain(false)
+	err = errs.Wrap(errs.Wrap(errs.New(nil, "Inner"), nil, "Outer"), nil)
+	assert(t, err.PublicMsg() == "Outer", "Expected replace with preserved chain", err.PublicMsg())
+}
+
+func TestHasInfo(t *testing.T) {
+	err := errs.New(errs.Info{"x": nil})
+	assert(t, err.HasInfo("x"), "Expected HasInfo to report a key with a nil value")
+	assert(t, !err.HasInfo("y"))
+	assert(t, !errs.New(nil).HasInfo("x"))
+}
+
+func TestMergeNilInfo(t *testing.T) {
+	defer errs.SetMergePolicy(errs.MergeSuffix)
+	err := errs.Wrap(errs.New(errs.Info{"x": nil}), errs.Info{"x": 1})
+	assert(t, err.HasInfo("x") && err.Info("x") == nil, "Expected the nil value to be kept", err.AllInfo())
+	assert(t, err.Info("x_duplicate") == 1, err.AllInfo())
+	vals := err.InfoAll("x")
+	assert(t, len(vals) == 2 && vals[0] == nil && vals[1] == 1, vals)
+
+	errs.SetMergePolicy(errs.MergeKeepFirst)
+	err = errs.Wrap(errs.New(errs.Info{"x": nil}), errs.Info{"x": 1})
+	assert(t, err.HasInfo("x") && err.Info("x") == nil, "Expected MergeKeepFirst to keep the nil value", err.AllInfo())
+}
+
+func TestWithRequestID(t *testing.T) {
+	err := errs.New(nil).WithRequestID("abc")
+	assert(t, err.Info(errs.KeyRequestID) == "abc", err.Info(errs.KeyRequestID))
+	err = err.WithTraceID("def")
+	assert(t, err.Info(errs.KeyTraceID) == "def", err.Info(errs.KeyTraceID))
+	assert(t, errs.KeyRequestID == "RequestID", "Expected canonical key spelling")
+
+	joined := errs.Join(errors.New("a"))
+	assert(t, joined.WithRequestID("abc") == joined, "Expected joined error to be returned")
+}
+
+func TestSetMaxInfoValueLen(t *testing.T) {
+	errs.SetMaxInfoValueLen(100)
+	defer errs.SetMaxInfoValueLen(0)
+	blob := strings.Repeat("x", 10*1024)
+	err := errs.New(errs.Info{"Blob": blob, "Bytes": []byte(blob), "Short": "short"})
+	truncated := strings.Repeat("x", 100) + "…(truncated)"
+	assert(t, strings.Contains(err.LogString(), "Blob="+truncated+" "), "Expected truncated value in LogString")
+	assert(t, !strings.Contains(err.LogString(), strings.Repeat("x", 101)), "Expected no full value in LogString")
+	assert(t, strings.Contains(err.LogString(), "Short=short"))
+
+	data, _ := json.Marshal(err)
+	var res struct{ Info map[string]interface{} }
+	assert(t, json.Unmarshal(data, &res) == nil)
+	assert(t, res.Info["Blob"] == truncated, "Expected truncated value in JSON")
+	assert(t, len(res.Info["Bytes"].(string)) < 200, "Expected truncated non-string value in JSON")
+	assert(t, err.Info("Blob") == blob, "Expected full value from Info")
+}
+
+func TestInfoKeys(t *testing.T) {
+	keys := errs.New(errs.Info{"c": 3, "a": 1, "b": 2}).InfoKeys()
+	assert(t, strings.Join(keys, ",") == "a,b,c", "Expected sorted keys", keys)
+	keys = errs.New(nil).InfoKeys()
+	assert(t, keys != nil && len(keys) == 0, "Expected empty non-nil keys", keys)
+}
+
+var errSentinel = errs.NewWithCode("SENTINEL", errs.Info{"Foo": "Bar"}, "publicMsg")
+
+func TestClone(t *testing.T) {
+	clone := errSentinel.Clone()
+	clone.WithInfo("RequestID", "abc").WithInfo("Foo", "Changed")
+	errs.Wrap(clone, errs.Info{"Cat": "Mat"}, "Outer")
+	assert(t, clone.Info("RequestID") == "abc" && clone.Info("Cat") == "Mat")
+	assert(t, !errSentinel.HasInfo("RequestID") && !errSentinel.HasInfo("Cat"), "Expected original info to be unchanged", errSentinel.AllInfo())
+	assert(t, errSentinel.Info("Foo") == "Bar", errSentinel.Info("Foo"))
+	assert(t, errSentinel.PublicMsg() == "publicMsg", errSentinel.PublicMsg())
+	assert(t, clone.PublicMsg() == "Outer - publicMsg", clone.PublicMsg())
+	assert(t, clone.Code() == "SENTINEL")
+	assert(t, clone.Time() == errSentinel.Time(), "Expected the time to be shared")
+	assert(t, string(clone.Stack()) == string(errSentinel.Stack()), "Expected the stack to be shared")
+
+	joined := errs.Join(errors.New("a"))
+	_, isMulti := joined.Clone().(interface{ Unwrap() []error })
+	assert(t, isMulti, "Expected a cloned joined error to keep its errors")
+}
+
+func TestInfoAll(t *testing.T) {
+	err := errs.New(errs.Info{"Query": "SELECT 1"})
+	errs.Wrap(err, errs.Info{"Query": "SELECT 2"})
+	errs.Wrap(err, errs.Info{"Query": errs.Secret("SELECT 3")})
+	vals := err.InfoAll("Query")
+	assert(t, len(vals) == 3 && vals[0] == "SELECT 1" && vals[1] == "SELECT 2" && vals[2] == "SELECT 3", "Expected all three values in order", vals)
+	assert(t, err.InfoAll("Missing") == nil)
+}
+
+func TestSanitize(t *testing.T) {
+	err := errs.UserErrorWrap(errors.New("sql: no rows"), errs.Info{"UserID": 1}, "Account not found").WithStatus(http.StatusNotFound)
+	errs.WrapWithCode("NOT_FOUND", err, errs.Info{"Query": "SELECT"}, "Lookup failed")
+	sanitized := err.Sanitize()
+	assert(t, len(sanitized.AllInfo()) == 0, "Expected no info", sanitized.AllInfo())
+	assert(t, sanitized.Stack() == nil, "Expected no stack", string(sanitized.Stack()))
+	assert(t, sanitized.WrappedError() == nil, "Expected no wrapped error")
+	assert(t, sanitized.PublicMsg() == "Lookup failed - Account not found", sanitized.PublicMsg())
+	assert(t, sanitized.Code() == "NOT_FOUND", sanitized.Code())
+	assert(t, sanitized.HTTPStatus() == http.StatusNotFound && sanitized.IsUserError())
+	assert(t, err.Info("UserID") == 1, "Expected the original to be unchanged")
+}
+
+func TestAllFields(t *testing.T) {
+	err := errs.WrapWithCode("CODE", errors.New("std"), errs.Info{"Foo": "Bar", "code": "collides", "info": 1, "Card": errs.Secret("4111")}, "publicMsg")
+	fields := err.AllFields()
+	for _, key := range []string{"publicMsg", "code", "userError", "time", "location", "Foo", "info", "Card"} {
+		_, hasKey := fields[key]
+		assert(t, hasKey, "Expected field", key)
+	}
+	assert(t, len(fields) == 8, fields)
+	assert(t, fields["publicMsg"] == "publicMsg" && fields["code"] == "CODE" && fields["userError"] == false)
+	assert(t, fields["time"] == err.Time() && fields["location"] == err.Location())
+	assert(t, fields["Foo"] == "Bar" && fields["Card"] == "[redacted]", fields)
+	info := fields["info"].(errs.Info)
+	assert(t, len(info) == 2 && info["code"] == "collides" && info["info"] == 1, "Expected colliding keys to be namespaced", info)
+
+	_, hasInfo := errs.New(nil).AllFields()["info"]
+	assert(t, !hasInfo, "Expected no info field without collisions")
+	assert(t, errs.Join(errs.New(nil, "a"), errs.New(nil, "b")).AllFields()["publicMsg"] == "a - b")
+}
@@ -0,0 +1,234 @@
+package errs
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// Join returns an errs.Err that aggregates all the non-nil given errors.
+// If all the given errors are nil, Join returns nil.
+// The returned Err implements `Unwrap() []error`, so errors.Is and
+// errors.As match against any of the joined errors. Its PublicMsg
+// is the concatenation of the public messages of the joined errors. It is a
+// user error if all of the joined errors are, e.g for form validation.
+func Join(errs ...error) Err {
+	var joined []error
+	isUserErr := true
+	for _, err := range errs {
+		if err != nil {
+			joined = append(joined, err)
+			isUserErr = isUserErr && errors.Is(err, ErrUser)
+		}
+	}
+	if len(joined) == 0 {
+		return nil
+	}
+	j := &joinedErr{buildErr(callers(0), nil, isUserErr, Info{}, nil), joined}
+	reportErr(j)
+	return j
+}
+
+// Internal
+///////////
+
+// gobJoinedErr is the gob representation of a joinedErr
+type gobJoinedErr struct {
+	Err    []byte
+	Errors []gobJoinedChild
+}
+
+// gobJoinedChild is the gob representation of a joined error,
+// with either the errs.Err or the error string of other errors
+type gobJoinedChild struct {
+	Err          Err
+	WrappedError string
+}
+
+// joinedErr implements Err for multiple errors
+type joinedErr struct {
+	*err
+	errs []error
+}
+
+// Unwrap returns the joined errors
+func (j *joinedErr) Unwrap() []error { return j.errs }
+
+func (j *joinedErr) Error() string  { return j.LogString() }
+func (j *joinedErr) String() string { return j.LogString() }
+
+// Implements Err. The With methods of err are overridden
+// in order to return the joinedErr rather than its base err.
+func (j *joinedErr) WithRetryable(retryable bool) Err { j.err.WithRetryable(retryable); return j }
+func (j *joinedErr) WithLevel(level Level) Err        { j.err.WithLevel(level); return j }
+func (j *joinedErr) WithStatus(status int) Err        { j.err.WithStatus(status); return j }
+func (j *joinedErr) WithInfo(key string, val interface{}) Err {
+	j.err.WithInfo(key, val)
+	return j
+}
+func (j *joinedErr) WithInfoMap(info Info) Err   { j.err.WithInfoMap(info); return j }
+func (j *joinedErr) WithRequestID(id string) Err { j.err.WithRequestID(id); return j }
+func (j *joinedErr) WithTraceID(id string) Err   { j.err.WithTraceID(id); return j }
+
+// Implements Err
+func (j *joinedErr) Clone() Err {
+	return &joinedErr{j.err.clone(), append([]error{}, j.errs...)}
+}
+
+// Implements Err. The public message of the joined
+// error is prepended with any messages added by Wrap.
+func (j *joinedErr) PublicMsg() string {
+	return joinStrings(j.PublicMsgParts())
+}
+
+// Implements Err. The parts are any messages added by Wrap,
+// followed by the public messages of the joined errors.
+func (j *joinedErr) PublicMsgParts() []string {
+	msgs := j.err.PublicMsgParts()
+	for _, child := range j.errs {
+		if errsErr, isErr := IsErr(child); isErr && errsErr.PublicMsg() != "" {
+			msgs = append(msgs, errsErr.PublicMsg())
+		}
+	}
+	return msgs
+}
+
+// MarshalJSON implements json.Marshaler, like err.MarshalJSON with the
+// joined public message, and the joined errors in an "errors" array.
+// Joined errors which are not errs.Errs are marshalled as their error strings,
+// e.g `{"wrappedError":"EOF"}`.
+func (j *joinedErr) MarshalJSON() ([]byte, error) {
+	jsonJ := j.err.toJSON()
+	jsonJ.PublicMsg = j.PublicMsg()
+	children := make([]interface{}, len(j.errs))
+	for i, child := range j.errs {
+		if errsErr, isErr := IsErr(child); isErr {
+			children[i] = errsErr
+		} else {
+			children[i] = map[string]string{"wrappedError": child.Error()}
+		}
+	}
+	return json.Marshal(struct {
+		jsonErr
+		Errors []interface{} `json:"errors"`
+	}{jsonJ, children})
+}
+
+// LogValue implements slog.LogValuer, like err.LogValue with the joined
+// public message, and the joined errors in an "errors" group keyed by index
+func (j *joinedErr) LogValue() slog.Value {
+	children := make([]slog.Attr, len(j.errs))
+	for i, child := range j.errs {
+		if errsErr, isErr := IsErr(child); isErr {
+			children[i] = slog.Any(strconv.Itoa(i), errsErr)
+		} else {
+			children[i] = slog.String(strconv.Itoa(i), child.Error())
+		}
+	}
+	return slog.GroupValue(append(j.err.logAttrs(j.PublicMsg()), slog.Attr{Key: "errors", Value: slog.GroupValue(children...)})...)
+}
+
+// GobEncode implements gob.GobEncoder, like err.GobEncode with the joined
+// errors in addition. Joined errors which are not errs.Errs are encoded as
+// their error strings, like wrapped errors.
+func (j *joinedErr) GobEncode() ([]byte, error) {
+	data, encodeErr := j.err.GobEncode()
+	if encodeErr != nil {
+		return nil, encodeErr
+	}
+	gobJ := gobJoinedErr{Err: data, Errors: make([]gobJoinedChild, len(j.errs))}
+	for i, child := range j.errs {
+		if errsErr, isErr := child.(Err); isErr {
+			gobJ.Errors[i].Err = errsErr
+		} else {
+			gobJ.Errors[i].WrappedError = child.Error()
+		}
+	}
+	var buf bytes.Buffer
+	if encodeErr := gob.NewEncoder(&buf).Encode(gobJ); encodeErr != nil {
+		return nil, encodeErr
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder, for errors encoded with GobEncode
+func (j *joinedErr) GobDecode(data []byte) error {
+	var gobJ gobJoinedErr
+	if decodeErr := gob.NewDecoder(bytes.NewReader(data)).Decode(&gobJ); decodeErr != nil {
+		return decodeErr
+	}
+	j.err = &err{}
+	if decodeErr := j.err.GobDecode(gobJ.Err); decodeErr != nil {
+		return decodeErr
+	}
+	j.errs = make([]error, len(gobJ.Errors))
+	for i, child := range gobJ.Errors {
+		if child.Err != nil {
+			j.errs[i] = child.Err
+		} else {
+			j.errs[i] = errors.New(child.WrappedError)
+		}
+	}
+	return nil
+}
+
+// Implements Err
+func (j *joinedErr) PublicJSON() ([]byte, error) {
+	return j.publicJSON(j.PublicMsg())
+}
+
+// Implements Err
+func (j *joinedErr) ProblemJSON() ([]byte, error) {
+	return j.problemJSON(j.PublicMsg())
+}
+
+// Implements Err
+func (j *joinedErr) AllFields() map[string]interface{} {
+	return j.allFields(j.PublicMsg())
+}
+
+// Implements Err
+func (j *joinedErr) Sanitize() Err {
+	return j.sanitize(j.PublicMsgParts())
+}
+
+// Implements Err
+func (j *joinedErr) Equal(other Err) bool {
+	return j.equal(j.PublicMsg(), other)
+}
+
+// Implements Err
+func (j *joinedErr) LogString() string {
+	parts := []interface{}{j.err.LogString(), "| Errors:", len(j.errs)}
+	for i, child := range j.errs {
+		parts = append(parts, fmt.Sprintf("\n[%d]", i), child.Error())
+	}
+	return concatArgs(parts...)
+}
+
+// Implements Err. Joined errs.Errs are rendered with their own
+// LogStringCompact, and other errors with escaped newlines.
+func (j *joinedErr) LogStringCompact() string {
+	parts := []interface{}{j.err.LogStringCompact(), "| Errors:", len(j.errs)}
+	for i, child := range j.errs {
+		childStr := escapeNewlines(child.Error())
+		if errsErr, isErr := IsErr(child); isErr {
+			childStr = errsErr.LogStringCompact()
+		}
+		parts = append(parts, fmt.Sprintf("[%d]", i), childStr)
+	}
+	return concatArgs(parts...)
+}
+
+// Implements fmt.Formatter. See err.Format
+func (j *joinedErr) Format(s fmt.State, verb rune) {
+	parts := []interface{}{j.err.summary(), "| Errors:", len(j.errs)}
+	for i, child := range j.errs {
+		parts = append(parts, fmt.Sprintf("[%d] %v", i, child))
+	}
+	formatErr(s, verb, concatArgs(parts...), j.cachedStack())
+}
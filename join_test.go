@@ -0,0 +1,88 @@
+package errs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestJoin(t *testing.T) {
+	err := errs.Join(
+		errs.UserError(nil, "Email is taken"),
+		nil,
+		errs.UserError(nil, "Password is too short"),
+		errs.UserError(nil, "Name is required"),
+	)
+	msg := err.PublicMsg()
+	assert(t, strings.Contains(msg, "Email is taken"))
+	assert(t, strings.Contains(msg, "Password is too short"))
+	assert(t, strings.Contains(msg, "Name is required"))
+	assert(t, strings.Contains(err.LogString(), "Errors: 3"))
+	assert(t, err.IsUserError(), "Expected a join of user errors to be a user error")
+	assert(t, err.HTTPStatus() == 400 && err.Level() == errs.LevelWarn, err.HTTPStatus(), err.Level())
+
+	err = errs.Join(errs.UserError(nil, "Email is taken"), fmt.Errorf("wrapped: %w", errs.UserError(nil)))
+	assert(t, err.IsUserError(), "Expected wrapped user errors to count as user errors")
+	err = errs.Join(errs.UserError(nil, "Email is taken"), errors.New("db down"))
+	assert(t, !err.IsUserError() && err.HTTPStatus() == 500, "Expected a join with a system error to be a system error")
+}
+
+func TestJoinJSON(t *testing.T) {
+	data, marshalErr := json.Marshal(errs.Join(errs.UserError(nil, "Email is taken"), io.EOF))
+	assert(t, marshalErr == nil, marshalErr)
+	var res struct {
+		PublicMsg string `json:"publicMsg"`
+		Errors    []struct {
+			PublicMsg    string `json:"publicMsg"`
+			WrappedError string `json:"wrappedError"`
+		} `json:"errors"`
+	}
+	assert(t, json.Unmarshal(data, &res) == nil, string(data))
+	assert(t, res.PublicMsg == "Email is taken", "Expected the joined public message", string(data))
+	assert(t, len(res.Errors) == 2 && res.Errors[0].PublicMsg == "Email is taken" && res.Errors[1].WrappedError == "EOF", string(data))
+}
+
+func TestJoinIs(t *testing.T) {
+	err := errs.Join(errs.New(nil), errs.Wrap(io.EOF, nil))
+	assert(t, errors.Is(err, io.EOF), "Expected errors.Is to match a joined error")
+	assert(t, !errors.Is(err, io.ErrUnexpectedEOF))
+}
+
+func TestJoinUnwrap(t *testing.T) {
+	_, openErr := os.Open("/does/not/exist")
+	target := errors.New("target")
+	err := errs.Join(
+		errs.New(nil),
+		fmt.Errorf("nested: %w", errs.Join(errors.New("other"), errs.Wrap(target, nil))),
+		errs.Wrap(openErr, nil),
+	)
+	multi, isMulti := err.(interface{ Unwrap() []error })
+	assert(t, isMulti, "Expected joined error to implement Unwrap() []error")
+	assert(t, len(multi.Unwrap()) == 3)
+	assert(t, errors.Is(err, target), "Expected errors.Is to find a nested joined target")
+	var pathErr *os.PathError
+	assert(t, errors.As(err, &pathErr), "Expected errors.As to fan out to all children")
+	assert(t, pathErr.Path == "/does/not/exist")
+
+	single := errs.Wrap(target, nil)
+	_, isMulti = single.(interface{ Unwrap() []error })
+	assert(t, !isMulti, "Expected single-wrap error not to implement Unwrap() []error")
+	assert(t, errors.Unwrap(single) == target, "Expected single-wrap error to implement Unwrap() error")
+}
+
+func TestJoinNil(t *testing.T) {
+	assert(t, errs.Join() == nil)
+	assert(t, errs.Join(nil, nil) == nil)
+}
+
+func TestWrapJoin(t *testing.T) {
+	err := errs.Wrap(errs.Join(errs.UserError(nil, "Inner")), errs.Info{"Foo": "Bar"}, "Outer")
+	assert(t, err.Info("Foo") == "Bar", "Expected Wrap to merge info into a joined error")
+	assert(t, err.PublicMsg() == "Outer - Inner")
+}
@@ -0,0 +1,192 @@
+package errs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// MarshalJSON implements json.Marshaler. Info values which
+// cannot be marshalled are rendered with fmt's %v rather than
+// failing the whole marshal.
+func (e *err) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.toJSON())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, for errors marshalled with
+// MarshalJSON. The unmarshalled error has no program counters, so StackFrames
+// returns nil, but Stack returns the stack text. Info numbers are float64s,
+// as with any JSON decoded into an interface{}.
+func (e *err) UnmarshalJSON(data []byte) error {
+	var jsonE jsonErr
+	if unmarshalErr := json.Unmarshal(data, &jsonE); unmarshalErr != nil {
+		return unmarshalErr
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.time = jsonE.Time
+	e.goroutineID = jsonE.GoroutineID
+	e.code = jsonE.Code
+	e.publicMsg = appendPublicMsg(nil, []interface{}{jsonE.PublicMsg})
+	e.info = Info(jsonE.Info)
+	if jsonE.WrappedError != "" {
+		e.wrappedErr = errors.New(jsonE.WrappedError)
+	}
+	e.isUserErr = jsonE.IsUserError
+	var stack []byte
+	if len(jsonE.Stack) > 0 {
+		stack = []byte(strings.Join(jsonE.Stack, "\n") + "\n")
+	}
+	e.stackOnce.Do(func() { e.stack = stack })
+	return nil
+}
+
+// FromJSON reconstructs an Err from the output of its MarshalJSON, e.g
+// for displaying errors from a log store. See UnmarshalJSON.
+func FromJSON(data []byte) (Err, error) {
+	e := &err{}
+	if unmarshalErr := e.UnmarshalJSON(data); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return e, nil
+}
+
+// Implements Err
+func (e *err) PublicJSON() ([]byte, error) {
+	return e.publicJSON(e.PublicMsg())
+}
+
+// Implements Err
+func (e *err) ProblemJSON() ([]byte, error) {
+	return e.problemJSON(e.PublicMsg())
+}
+
+// SetProblemInfoKeys sets the info keys which ProblemJSON includes as
+// extension members, e.g `errs.SetProblemInfoKeys("RequestID")`. The default
+// is none, so that no internal info is leaked to API clients by accident.
+// An "instance" info key, if given, sets the problem's instance member.
+func SetProblemInfoKeys(keys ...string) {
+	keys = append([]string{}, keys...)
+	problemInfoKeys.Store(&keys)
+}
+
+// Internal
+///////////
+
+var problemInfoKeys atomic.Pointer[[]string]
+
+// jsonPublicErr is the public JSON representation of an err
+type jsonPublicErr struct {
+	Message string `json:"message"`
+	Code    string `json:"code"`
+	Status  int    `json:"status,omitempty"`
+}
+
+// Marshal the public JSON of the error with the given public message.
+// The status is only included if it was explicitly given.
+func (e *err) publicJSON(publicMsg string) ([]byte, error) {
+	return json.Marshal(e.toPublicJSON(publicMsg))
+}
+
+// Get the public JSON representation of the error with the given public message
+func (e *err) toPublicJSON(publicMsg string) jsonPublicErr {
+	v := e.view()
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return jsonPublicErr{Message: publicMsg, Code: v.code, Status: v.httpStatus}
+}
+
+// Get the JSON representation of the error
+func (e *err) toJSON() jsonErr {
+	if e.chained {
+		return e.view().toJSON()
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return jsonErr{
+		Time:         e.TimeUTC(),
+		GoroutineID:  e.goroutineID,
+		Code:         e.code,
+		PublicMsg:    e.publicMsgStr(),
+		Info:         jsonInfo(filterInfo(e.info)),
+		WrappedError: e.wrappedErrStr(),
+		IsUserError:  e.isUserErr,
+		Stack:        stackLines(e.cachedStack()),
+	}
+}
+
+// jsonErr is the JSON representation of an err
+type jsonErr struct {
+	Time         time.Time              `json:"time"`
+	GoroutineID  uint64                 `json:"goroutineID,omitempty"`
+	Code         string                 `json:"code"`
+	PublicMsg    string                 `json:"publicMsg"`
+	Info         map[string]interface{} `json:"info"`
+	WrappedError string                 `json:"wrappedError"`
+	IsUserError  bool                   `json:"isUserError"`
+	Stack        []string               `json:"stack"`
+}
+
+// Marshal the RFC 7807 problem JSON of the error with the given public message
+func (e *err) problemJSON(publicMsg string) ([]byte, error) {
+	return json.Marshal(e.toProblem(publicMsg))
+}
+
+// Get the RFC 7807 problem members of the error with the given public message
+func (e *err) toProblem(publicMsg string) map[string]interface{} {
+	status := e.HTTPStatus()
+	v := e.view()
+	v.mu.RLock()
+	title := v.code
+	if title == "" {
+		title = http.StatusText(status)
+	}
+	problem := map[string]interface{}{}
+	if keys := problemInfoKeys.Load(); keys != nil {
+		extensions := Info{}
+		for _, key := range *keys {
+			if val, hasKey := v.info[key]; hasKey {
+				extensions[key] = val
+			}
+		}
+		problem = jsonInfo(extensions)
+	}
+	v.mu.RUnlock()
+	problem["type"] = "about:blank"
+	problem["title"] = title
+	problem["status"] = status
+	if publicMsg != "" {
+		problem["detail"] = publicMsg
+	}
+	if instance, isStr := problem["instance"].(string); !isStr || instance == "" {
+		delete(problem, "instance")
+	}
+	return problem
+}
+
+// Get a copy of info in which values that fail to marshal
+// are replaced with their %v string representations
+func jsonInfo(info Info) map[string]interface{} {
+	res := make(map[string]interface{}, len(info))
+	for key, val := range info {
+		val = renderValue(val)
+		if _, marshalErr := json.Marshal(val); marshalErr != nil {
+			res[key] = fmt.Sprintf("%v", val)
+		} else {
+			res[key] = val
+		}
+	}
+	return res
+}
+
+// Split a textual stack into its lines, without the trailing empty line
+func stackLines(stack []byte) []string {
+	if len(stack) == 0 {
+		return []string{}
+	}
+	return strings.Split(strings.TrimRight(string(stack), "\n"), "\n")
+}
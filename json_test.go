@@ -0,0 +1,95 @@
+package errs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	err := errs.Wrap(errors.New("It broke!"), errs.Info{"Foo": "Bar", "Fn": func() {}}, "publicMsg")
+	data, marshalErr := json.Marshal(err)
+	assert(t, marshalErr == nil, "Expected marshal to succeed", marshalErr)
+
+	var res map[string]interface{}
+	assert(t, json.Unmarshal(data, &res) == nil, "Expected valid JSON")
+	for _, key := range []string{"time", "publicMsg", "info", "wrappedError", "isUserError", "stack", "code"} {
+		_, hasKey := res[key]
+		assert(t, hasKey, "Expected JSON key", key)
+	}
+	assert(t, res["publicMsg"] == "publicMsg")
+	assert(t, res["wrappedError"] == "It broke!")
+	assert(t, res["isUserError"] == false)
+	info := res["info"].(map[string]interface{})
+	assert(t, info["Foo"] == "Bar")
+	_, isStr := info["Fn"].(string)
+	assert(t, isStr, "Expected unmarshallable info value to be rendered as a string")
+	stack := res["stack"].([]interface{})
+	assert(t, len(stack) > 1, "Expected stack lines")
+}
+
+func TestPublicJSON(t *testing.T) {
+	err := errs.WrapWithCode("BAD_LOGIN", errors.New("secret-internal"), errs.Info{"Password": "hunter2"}, "Wrong username/password")
+	data, marshalErr := err.PublicJSON()
+	assert(t, marshalErr == nil, "Expected marshal to succeed", marshalErr)
+	str := string(data)
+	assert(t, str == `{"message":"Wrong username/password","code":"BAD_LOGIN"}`, str)
+	for _, leak := range []string{"Password", "hunter2", "secret-internal", "stack", ".go"} {
+		assert(t, !strings.Contains(str, leak), "Expected public JSON not to contain", leak)
+	}
+
+	data, _ = errs.HTTPError(401, nil, "Unauthorized").PublicJSON()
+	assert(t, string(data) == `{"message":"Unauthorized","code":"","status":401}`, string(data))
+
+	data, _ = errs.Join(errs.New(nil, "first"), errs.New(nil, "second")).PublicJSON()
+	assert(t, string(data) == `{"message":"first - second","code":""}`, string(data))
+}
+
+func TestProblemJSON(t *testing.T) {
+	err := errs.HTTPError(404, errs.Info{"RequestID": "req-1", "instance": "/users/1", "Secret": "s"}, "User not found")
+	data, marshalErr := err.ProblemJSON()
+	assert(t, marshalErr == nil, "Expected marshal to succeed", marshalErr)
+	var res map[string]interface{}
+	assert(t, json.Unmarshal(data, &res) == nil, "Expected valid JSON")
+	assert(t, res["status"] == float64(404), res["status"])
+	assert(t, res["detail"] == "User not found", res["detail"])
+	assert(t, res["title"] == "Not Found", res["title"])
+	assert(t, res["type"] == "about:blank", res["type"])
+	assert(t, len(res) == 4, "Expected no info to be included by default", string(data))
+
+	errs.SetProblemInfoKeys("RequestID", "instance")
+	defer errs.SetProblemInfoKeys()
+	data, _ = errs.NewWithCode("USER_NOT_FOUND", errs.Info{"RequestID": "req-1", "instance": "/users/1", "Secret": "s"}).ProblemJSON()
+	res = nil
+	assert(t, json.Unmarshal(data, &res) == nil, "Expected valid JSON")
+	assert(t, res["title"] == "USER_NOT_FOUND", res["title"])
+	assert(t, res["status"] == float64(500), res["status"])
+	assert(t, res["RequestID"] == "req-1", res["RequestID"])
+	assert(t, res["instance"] == "/users/1", res["instance"])
+	_, hasSecret := res["Secret"]
+	assert(t, !hasSecret, "Expected non-whitelisted info to be omitted")
+	_, hasDetail := res["detail"]
+	assert(t, !hasDetail, "Expected empty detail to be omitted")
+}
+
+func TestFromJSON(t *testing.T) {
+	orig := errs.WrapWithCode("CODE", errors.New("std"), errs.Info{"Foo": "Bar"}, "publicMsg")
+	data, _ := json.Marshal(orig)
+	err, unmarshalErr := errs.FromJSON(data)
+	assert(t, unmarshalErr == nil, "Expected unmarshal to succeed", unmarshalErr)
+	assert(t, err.PublicMsg() == orig.PublicMsg(), err.PublicMsg())
+	assert(t, err.Code() == orig.Code())
+	assert(t, err.Info("Foo") == "Bar" && len(err.AllInfo()) == 1, err.AllInfo())
+	assert(t, err.WrappedError().Error() == "std")
+	assert(t, !err.IsUserError())
+	assert(t, err.Time().Equal(orig.Time()), err.Time(), orig.Time())
+	assert(t, string(err.Stack()) == string(orig.Stack()), "Expected stack text to survive", string(err.Stack()))
+	data2, _ := json.Marshal(err)
+	assert(t, string(data2) == string(data), "Expected identical JSON after round-trip", string(data2))
+
+	_, unmarshalErr = errs.FromJSON([]byte("not json"))
+	assert(t, unmarshalErr != nil, "Expected invalid JSON to fail")
+}
@@ -0,0 +1,73 @@
+package errs
+
+import "strconv"
+
+// Level is the severity of an error, e.g for routing logs
+type Level int
+
+// Levels, in order of increasing severity
+const (
+	LevelDebug Level = iota + 1
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+// String returns the name of the level, e.g "Error"
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "Debug"
+	case LevelInfo:
+		return "Info"
+	case LevelWarn:
+		return "Warn"
+	case LevelError:
+		return "Error"
+	case LevelFatal:
+		return "Fatal"
+	default:
+		return "Level(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+// NewLevel creates a new Err with the given level, Info and optional public message.
+// See Err.Level
+func NewLevel(level Level, info Info, publicMsg ...interface{}) Err {
+	return newErr(callers(0), nil, false, info, publicMsg, withLevel(level))
+}
+
+// Implements Err
+func (e *err) Level() Level {
+	if e.chained {
+		return e.view().Level()
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.levelLocked()
+}
+
+// Implements Err
+func (e *err) WithLevel(level Level) Err {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.level = level
+	return e
+}
+
+// Internal
+///////////
+
+func withLevel(level Level) errOption { return func(e *err) { e.level = level } }
+
+// Get the level, or its default if it has not been set. The caller must hold e.mu
+func (e *err) levelLocked() Level {
+	if e.level != 0 {
+		return e.level
+	}
+	if e.isUserErr {
+		return LevelWarn
+	}
+	return LevelError
+}
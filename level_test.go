@@ -0,0 +1,22 @@
+package errs_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestLevel(t *testing.T) {
+	assert(t, errs.New(nil).Level() == errs.LevelError, "Expected default level to be LevelError")
+	assert(t, errs.UserError(nil).Level() == errs.LevelWarn, "Expected default user error level to be LevelWarn")
+	assert(t, errs.NewLevel(errs.LevelFatal, nil).Level() == errs.LevelFatal)
+	assert(t, errs.Wrap(io.EOF, nil).WithLevel(errs.LevelDebug).Level() == errs.LevelDebug)
+	assert(t, errs.Wrap(errs.NewLevel(errs.LevelInfo, nil), nil).Level() == errs.LevelInfo, "Expected level to survive Wrap")
+
+	assert(t, strings.HasPrefix(errs.New(nil).LogString(), "Error | "))
+	assert(t, strings.HasPrefix(errs.UserError(nil).LogString(), "Warn | "))
+	assert(t, strings.HasPrefix(errs.NewLevel(errs.LevelFatal, nil).LogString(), "Fatal | "))
+	assert(t, errs.Level(0).String() == "Level(0)")
+}
@@ -0,0 +1,43 @@
+package errs
+
+// Localized creates a new Err whose public message is a localization key
+// with args, rather than a message in a specific language, so that e.g
+// middleware or the frontend can translate it. PublicMsg falls back to the
+// key. See Err.LocalizationKey and Err.LocalizationArgs,
+// e.g `errs.Localized(nil, "error.email_taken", map[string]interface{}{"Email": email})`
+func Localized(info Info, key string, args map[string]interface{}) Err {
+	return newErr(callers(0), nil, false, info, []interface{}{key}, withLocalization(key, args))
+}
+
+// Implements Err
+func (e *err) LocalizationKey() string {
+	return e.view().localizationKey
+}
+
+// Implements Err
+func (e *err) LocalizationArgs() map[string]interface{} {
+	args := e.view().localizationArgs
+	if args == nil {
+		return nil
+	}
+	res := make(map[string]interface{}, len(args))
+	for key, val := range args {
+		res[key] = val
+	}
+	return res
+}
+
+// Internal
+///////////
+
+func withLocalization(key string, args map[string]interface{}) errOption {
+	return func(e *err) {
+		e.localizationKey = key
+		if len(args) > 0 {
+			e.localizationArgs = make(map[string]interface{}, len(args))
+			for argKey, val := range args {
+				e.localizationArgs[argKey] = val
+			}
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package errs_test
+
+import (
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestLocalized(t *testing.T) {
+	args := map[string]interface{}{"Email": "foo@bar.com"}
+	err := errs.Localized(errs.Info{"UserID": 1}, "error.email_taken", args)
+	args["Email"] = "changed"
+	assert(t, err.LocalizationKey() == "error.email_taken", err.LocalizationKey())
+	assert(t, err.LocalizationArgs()["Email"] == "foo@bar.com", "Expected the args to be copied", err.LocalizationArgs())
+	assert(t, err.PublicMsg() == "error.email_taken", "Expected PublicMsg to fall back to the key", err.PublicMsg())
+	assert(t, err.Info("UserID") == 1)
+
+	errs.SetPreserveChain(true)
+	defer errs.SetPreserveChain(false)
+	wrapped := errs.Wrap(err, nil)
+	assert(t, wrapped.LocalizationKey() == "error.email_taken", "Expected the key to survive wrapping")
+	assert(t, wrapped.Sanitize().LocalizationArgs()["Email"] == "foo@bar.com", "Expected the args to survive Sanitize")
+
+	plain := errs.New(nil, "Plain")
+	assert(t, plain.LocalizationKey() == "" && plain.LocalizationArgs() == nil)
+}
@@ -0,0 +1,96 @@
+package errs
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// EnableRecentBuffer keeps the n most recently created errors in memory,
+// for triage with RecentHandler. Calling it again resizes the buffer and
+// clears it, and n <= 0 disables it. The errors are collected with an
+// OnError hook, which is registered on the first call.
+func EnableRecentBuffer(n int) {
+	recentOnce.Do(func() { OnError(recent.add) })
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+	recent.errs = make([]Err, 0, max(n, 0))
+	recent.next = 0
+}
+
+// RecentErrors returns the errors in the buffer enabled
+// with EnableRecentBuffer, with the newest error first.
+func RecentErrors() []Err {
+	return recent.list()
+}
+
+// RecentHandler returns an http.Handler which renders the errors in the buffer
+// enabled with EnableRecentBuffer as a JSON array, with the newest error first.
+// Each error has its time, code, publicMsg, and the top frames of its stack.
+// Since the errors include internal details, only serve it on an internal port,
+// e.g `debugMux.Handle("/debug/errors", errs.RecentHandler())`
+func RecentHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		errs := recent.list()
+		res := make([]jsonRecentErr, len(errs))
+		for i, err := range errs {
+			stack := stackLines(err.Stack())
+			if len(stack) > 2*recentStackFrames {
+				stack = stack[:2*recentStackFrames]
+			}
+			res[i] = jsonRecentErr{err.TimeUTC(), err.Code(), err.PublicMsg(), stack}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	})
+}
+
+// Internal
+///////////
+
+// The number of stack frames rendered by RecentHandler
+const recentStackFrames = 10
+
+var (
+	recentOnce sync.Once
+	recent     recentBuffer
+)
+
+// recentBuffer is a ring buffer of errors
+type recentBuffer struct {
+	mu   sync.Mutex
+	errs []Err
+	next int // The index of the oldest error when the buffer is full
+}
+
+// Add an error to the buffer, replacing the oldest if it is full
+func (b *recentBuffer) add(err Err) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.errs) < cap(b.errs) {
+		b.errs = append(b.errs, err)
+	} else if len(b.errs) > 0 {
+		b.errs[b.next] = err
+		b.next = (b.next + 1) % len(b.errs)
+	}
+}
+
+// Get the errors in the buffer, with the newest first
+func (b *recentBuffer) list() []Err {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	res := make([]Err, len(b.errs))
+	for i := range res {
+		res[i] = b.errs[(b.next+len(b.errs)-1-i)%len(b.errs)]
+	}
+	return res
+}
+
+// jsonRecentErr is the JSON representation of an error rendered by RecentHandler
+type jsonRecentErr struct {
+	Time      time.Time `json:"time"`
+	Code      string    `json:"code"`
+	PublicMsg string    `json:"publicMsg"`
+	Stack     []string  `json:"stack"`
+}
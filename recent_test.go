@@ -0,0 +1,48 @@
+package errs_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestRecentBuffer(t *testing.T) {
+	errs.EnableRecentBuffer(10)
+	defer errs.EnableRecentBuffer(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() { defer wg.Done(); errs.New(nil) }()
+	}
+	wg.Wait()
+	for i := 0; i < 10; i++ {
+		errs.NewWithCode(fmt.Sprint("CODE_", i), nil, "msg", i)
+	}
+	recent := errs.RecentErrors()
+	assert(t, len(recent) == 10, "Expected only the most recent 10 errors", len(recent))
+	for i, err := range recent {
+		assert(t, err.Code() == fmt.Sprint("CODE_", 9-i), "Expected newest first", i, err.Code())
+	}
+
+	recorder := httptest.NewRecorder()
+	errs.RecentHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/errors", nil))
+	assert(t, recorder.Header().Get("Content-Type") == "application/json")
+	var res []struct {
+		Code      string
+		PublicMsg string
+		Stack     []string
+	}
+	assert(t, json.Unmarshal(recorder.Body.Bytes(), &res) == nil, recorder.Body.String())
+	assert(t, len(res) == 10 && res[0].Code == "CODE_9" && res[0].PublicMsg == "msg 9", res)
+	assert(t, len(res[0].Stack) > 0 && len(res[0].Stack) <= 20, "Expected a trimmed stack", len(res[0].Stack))
+	assert(t, strings.Contains(res[0].Stack[0], "TestRecentBuffer"), res[0].Stack)
+
+	errs.EnableRecentBuffer(0)
+	errs.New(nil)
+	assert(t, len(errs.RecentErrors()) == 0, "Expected a disabled buffer to be empty")
+}
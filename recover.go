@@ -0,0 +1,45 @@
+package errs
+
+import (
+	"runtime"
+	"strings"
+)
+
+// Recover converts a panic into an errs.Err, and stores it in *errp.
+// It must be deferred directly, e.g at the top of a handler:
+//
+//	func handle() (err error) {
+//		defer errs.Recover(&err)
+//		...
+//	}
+//
+// The panic value is stored in Info("panic"). If it is an error then it
+// is also the wrapped error. The stack is captured at the panic site.
+// If there was no panic then *errp is left untouched.
+func Recover(errp *error) {
+	panicVal := recover()
+	if panicVal == nil {
+		return
+	}
+	wrappedErr, _ := panicVal.(error)
+	*errp = newErr(panicCallers(), wrappedErr, false, Info{"panic": panicVal}, nil)
+}
+
+// Internal
+///////////
+
+// Get the callers of a deferred function during a panic,
+// with the runtime's panic handling frames trimmed.
+func panicCallers() []uintptr {
+	pcs := callers(0)
+	for len(pcs) > 0 && isRuntimePC(pcs[0]) {
+		pcs = pcs[1:]
+	}
+	return pcs
+}
+
+// Check if the given return program counter is inside the runtime package
+func isRuntimePC(pc uintptr) bool {
+	fn := runtime.FuncForPC(pc - 1)
+	return fn != nil && strings.HasPrefix(fn.Name(), "runtime.")
+}
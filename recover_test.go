@@ -0,0 +1,46 @@
+package errs_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestRecoverString(t *testing.T) {
+	err := panicWith("Oh no")
+	errsErr, isErr := errs.IsErr(err)
+	assert(t, isErr, "Expected Recover to set an errs.Err")
+	assert(t, errsErr.Info("panic") == "Oh no")
+	assert(t, errsErr.WrappedError() == nil)
+	frames := errsErr.StackFrames()
+	assert(t, strings.HasSuffix(frames[0].Func, ".doPanic"), "Expected stack to start at the panic site", frames[0].Func)
+}
+
+func TestRecoverError(t *testing.T) {
+	err := panicWith(io.EOF)
+	errsErr, isErr := errs.IsErr(err)
+	assert(t, isErr, "Expected Recover to set an errs.Err")
+	assert(t, errsErr.Info("panic") == io.EOF)
+	assert(t, errors.Is(err, io.EOF), "Expected the panic error to be wrapped")
+}
+
+func TestRecoverNoPanic(t *testing.T) {
+	err := panicWith(nil)
+	assert(t, err == io.ErrUnexpectedEOF, "Expected Recover to leave err untouched")
+}
+
+func panicWith(val interface{}) (err error) {
+	defer errs.Recover(&err)
+	err = io.ErrUnexpectedEOF
+	if val != nil {
+		doPanic(val)
+	}
+	return err
+}
+
+func doPanic(val interface{}) {
+	panic(val)
+}
@@ -0,0 +1,56 @@
+package errs
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// LogValue implements slog.LogValuer, so that e.g
+// `slog.Error("failed", "err", err)` logs the error as structured
+// attributes rather than as one string.
+func (e *err) LogValue() slog.Value {
+	return slog.GroupValue(e.logAttrs(e.PublicMsg())...)
+}
+
+// Internal
+///////////
+
+// Get the LogValue attributes of the error with the given public message
+func (e *err) logAttrs(publicMsg string) []slog.Attr {
+	if e.chained {
+		return e.view().logAttrs(publicMsg)
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	attrs := []slog.Attr{
+		slog.Time("time", e.time),
+		slog.String("publicMsg", publicMsg),
+	}
+	if e.code != "" {
+		attrs = append(attrs, slog.String("code", e.code))
+	}
+	if e.wrappedErr != nil {
+		attrs = append(attrs, slog.String("wrappedError", e.wrappedErrStr()))
+	}
+	if len(e.info) > 0 {
+		attrs = append(attrs, slog.Attr{Key: "info", Value: slog.GroupValue(infoAttrs(filterInfo(e.info))...)})
+	}
+	if frames := e.StackFrames(); len(frames) > 0 {
+		stack := make([]string, len(frames))
+		for i, frame := range frames {
+			stack[i] = fmt.Sprintf("%s %s:%d", frame.Func, frame.File, frame.Line)
+		}
+		attrs = append(attrs, slog.Any("stack", truncateStackLines(stack)))
+	}
+	return attrs
+}
+
+// Get the info as sorted slog attributes
+func infoAttrs(info Info) []slog.Attr {
+	keys := sortedKeys(info)
+	attrs := make([]slog.Attr, len(keys))
+	for i, key := range keys {
+		attrs[i] = slog.Any(key, renderValue(info[key]))
+	}
+	return attrs
+}
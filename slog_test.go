@@ -0,0 +1,66 @@
+package errs_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("failed", "err", errs.NewWithCode("CODE", errs.Info{"Foo": "Bar"}, "publicMsg"))
+
+	var res struct {
+		Err struct {
+			PublicMsg string            `json:"publicMsg"`
+			Code      string            `json:"code"`
+			Info      map[string]string `json:"info"`
+			Stack     []string          `json:"stack"`
+		} `json:"err"`
+	}
+	assert(t, json.Unmarshal(buf.Bytes(), &res) == nil, "Expected JSON log output", buf.String())
+	assert(t, res.Err.Info["Foo"] == "Bar", "Expected info.Foo attribute", buf.String())
+	assert(t, res.Err.PublicMsg == "publicMsg")
+	assert(t, res.Err.Code == "CODE")
+	assert(t, len(res.Err.Stack) > 0, "Expected stack attribute")
+}
+
+func TestLogValueJoined(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("failed", "err", errs.Join(errs.New(nil, "first"), io.EOF))
+
+	var res struct {
+		Err struct {
+			PublicMsg string `json:"publicMsg"`
+			Errors    struct {
+				First struct {
+					PublicMsg string `json:"publicMsg"`
+				} `json:"0"`
+				Second string `json:"1"`
+			} `json:"errors"`
+		} `json:"err"`
+	}
+	assert(t, json.Unmarshal(buf.Bytes(), &res) == nil, "Expected JSON log output", buf.String())
+	assert(t, res.Err.PublicMsg == "first", "Expected the joined public message", buf.String())
+	assert(t, res.Err.Errors.First.PublicMsg == "first" && res.Err.Errors.Second == "EOF", "Expected the joined errors", buf.String())
+}
+
+func TestLogValueValidation(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("failed", "err", errs.Validation().Field("email", "taken"))
+
+	var res struct {
+		Err struct {
+			Fields map[string]string `json:"fields"`
+		} `json:"err"`
+	}
+	assert(t, json.Unmarshal(buf.Bytes(), &res) == nil, "Expected JSON log output", buf.String())
+	assert(t, res.Err.Fields["email"] == "taken", "Expected the fields", buf.String())
+}
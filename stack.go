@@ -0,0 +1,422 @@
+package errs
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"path"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Frame is a single resolved stack frame, as returned by Err.StackFrames.
+type Frame struct {
+	// Func is the fully qualified function name, e.g "github.com/foo/bar.Baz"
+	Func string
+	// File is the absolute path of the source file
+	File string
+	// Line is the line number in File
+	Line int
+	// PC is the program counter for this frame
+	PC uintptr
+}
+
+// Format implements fmt.Formatter, with the same verbs as
+// github.com/pkg/errors' Frame:
+//
+//	%s    source file name
+//	%d    source line
+//	%n    function name
+//	%v    equivalent to %s:%d
+//	%+s   function name and path of source file, separated by \n\t
+//	%+v   equivalent to %+s:%d
+func (f Frame) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 's':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%s\n\t%s", f.Func, f.File)
+		} else {
+			io.WriteString(s, path.Base(f.File))
+		}
+	case 'd':
+		fmt.Fprintf(s, "%d", f.Line)
+	case 'n':
+		io.WriteString(s, strings.TrimPrefix(f.Func, funcPackage(f.Func)+"."))
+	case 'v':
+		f.Format(s, 's')
+		io.WriteString(s, ":")
+		f.Format(s, 'd')
+	}
+}
+
+// StackTrace is a stack of Frames, outermost call last. It supports the same
+// formatting verbs as github.com/pkg/errors' StackTrace, e.g %+v prints
+// each frame on its own lines. Only the formatting is compatible: a Frame
+// is a resolved struct rather than a program counter.
+type StackTrace []Frame
+
+// Format implements fmt.Formatter. %+v and %+s print each frame on new lines,
+// while the other verbs print the frames as a list, e.g "[file.go:1 file.go:2]"
+func (st StackTrace) Format(s fmt.State, verb rune) {
+	if s.Flag('+') && (verb == 'v' || verb == 's') {
+		for _, f := range st {
+			io.WriteString(s, "\n")
+			f.Format(s, verb)
+		}
+		return
+	}
+	io.WriteString(s, "[")
+	for i, f := range st {
+		if i > 0 {
+			io.WriteString(s, " ")
+		}
+		f.Format(s, verb)
+	}
+	io.WriteString(s, "]")
+}
+
+// SetMaxStackDepth limits how many stack frames are captured and rendered
+// for errors. The default, 0, means unlimited.
+func SetMaxStackDepth(n int) {
+	maxStackDepth.Store(int64(n))
+}
+
+// SetMaxStackBytes limits the size of the textual stacks returned by
+// Err.Stack and rendered in logs, e.g by LogStringCompact and LogValue, to
+// n bytes. Longer stacks are truncated, ending with a "...[truncated]"
+// marker. Unlike SetMaxStackDepth this also bounds stacks with very long
+// frames. Err.Stack is rendered once per error, so for it the limit applies
+// to stacks which have not been rendered yet. The default, 0, means unlimited.
+func SetMaxStackBytes(n int) {
+	maxStackBytes.Store(int64(n))
+}
+
+// SetStackSampleRate sets the fraction of errors which capture stacks, from
+// 0 (none) to 1 (all, the default). Errors which are not sampled have nil
+// stacks, like errors created with errs.NewNoStack. This reduces the cost of
+// creating errors in high-throughput services. Sampling is deterministic
+// rather than random: of every n consecutively created errors, n*rate
+// (rounded down) capture stacks.
+func SetStackSampleRate(rate float64) {
+	rate = max(0, min(rate, 1))
+	stackSampleRate.Store(&rate)
+	stackSampleCount.Store(0)
+}
+
+// SetTrimStack controls whether stack frames inside the errs package are
+// trimmed from the top of captured stacks, such that the first frame is the
+// caller of e.g errs.New. The default is true.
+func SetTrimStack(trim bool) {
+	noTrimStack.Store(!trim)
+}
+
+// SetSourceLinkPrefix makes StackLinks render the frames of source files
+// inside the root directory as prefix + the path relative to root + "#L" +
+// the line, e.g for GitHub permalinks generated in CI with
+// `errs.SetSourceLinkPrefix(workspaceDir, "https://github.com/org/repo/blob/"+commit)`.
+// Frames outside of root keep the "path:line" form. An empty prefix
+// restores the default.
+func SetSourceLinkPrefix(root, prefix string) {
+	if prefix == "" {
+		sourceLink.Store(nil)
+		return
+	}
+	sourceLink.Store(&sourceLinkPrefix{strings.TrimSuffix(root, "/") + "/", strings.TrimSuffix(prefix, "/") + "/"})
+}
+
+// Implements Err. Stack returns a copy of the cached
+// stack, which the caller is free to modify.
+func (e *err) Stack() []byte {
+	stack := e.cachedStack()
+	if stack == nil {
+		return nil
+	}
+	return append([]byte{}, stack...)
+}
+
+// Implements Err
+func (e *err) StackFrames() []Frame { return resolveFrames(e.pcs) }
+
+// Implements Err
+func (e *err) StackTrace() StackTrace { return StackTrace(e.StackFrames()) }
+
+// Implements Err
+func (e *err) StackLinks() []string {
+	frames := e.StackFrames()
+	if frames == nil {
+		return nil
+	}
+	link := sourceLink.Load()
+	links := make([]string, len(frames))
+	for i, frame := range frames {
+		if link != nil && strings.HasPrefix(frame.File, link.root) {
+			links[i] = link.prefix + strings.TrimPrefix(frame.File, link.root) + "#L" + strconv.Itoa(frame.Line)
+		} else {
+			links[i] = frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+	}
+	return links
+}
+
+// Implements Err
+func (e *err) AppFrames(modulePrefix string) []Frame {
+	if modulePrefix == "" {
+		modulePrefix = mainModulePath
+		if modulePrefix == "" {
+			return nil
+		}
+	}
+	var frames []Frame
+	for _, frame := range e.StackFrames() {
+		if strings.Contains(frame.Func, modulePrefix) || strings.Contains(frame.File, modulePrefix) {
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
+
+// Implements Err
+func (e *err) AllStacks() []byte {
+	if e.allStacks == nil && e.chained {
+		return e.wrappedErr.(Err).AllStacks()
+	}
+	return e.allStacks
+}
+
+// Implements Err
+func (e *err) Location() string {
+	for _, pc := range e.pcs {
+		if isErrsPC(pc) {
+			continue
+		}
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		return path.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+	}
+	return ""
+}
+
+// Implements Err
+func (e *err) Callers() []uintptr { return append([]uintptr{}, e.pcs...) }
+
+// Implements Err
+func (e *err) Fingerprint() string {
+	hash := fnv.New64a()
+	io.WriteString(hash, e.Code())
+	numFrames := 0
+	for _, frame := range e.StackFrames() {
+		if strings.HasPrefix(frame.Func, "runtime.") {
+			continue
+		}
+		fmt.Fprintf(hash, "\n%s %s:%d", frame.Func, frame.File, frame.Line)
+		numFrames++
+		if numFrames == fingerprintFrames {
+			break
+		}
+	}
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+// Internal
+///////////
+
+// The number of application stack frames used to compute Fingerprint
+const fingerprintFrames = 5
+
+var (
+	maxStackDepth atomic.Int64
+	maxStackBytes atomic.Int64
+	noTrimStack   atomic.Bool
+	// The rate set with SetStackSampleRate, or nil for 1
+	stackSampleRate atomic.Pointer[float64]
+	// The number of errors which have been considered for sampling
+	stackSampleCount atomic.Uint64
+	// The prefix set with SetSourceLinkPrefix, or nil for none
+	sourceLink atomic.Pointer[sourceLinkPrefix]
+)
+
+// A source root directory and the link prefix which replaces it
+type sourceLinkPrefix struct {
+	root   string
+	prefix string
+}
+
+// The path of the main module, or "" if it is not known
+var mainModulePath = func() string {
+	if info, hasInfo := debug.ReadBuildInfo(); hasInfo {
+		return info.Main.Path
+	}
+	return ""
+}()
+
+// The package prefix of all function names in this package,
+// e.g "github.com/marcuswestin/go-errs."
+var errsFuncPrefix = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	return funcPackage(runtime.FuncForPC(pc).Name()) + "."
+}()
+
+// The number of extra frames to capture when the stack depth is limited,
+// to leave room for trimming errs frames
+const trimSlack = 8
+
+// callers returns the program counters of the calling goroutine's stack,
+// starting at the function that called callers. Unless disabled with
+// SetTrimStack, leading frames inside the errs package are trimmed.
+// Another skip frames following the errs frames are always removed.
+// A negative skip is treated as 0.
+func callers(skip int) []uintptr {
+	if !sampleStack() {
+		return nil
+	}
+	if skip < 0 {
+		skip = 0
+	}
+	maxDepth := int(maxStackDepth.Load())
+	var pcs []uintptr
+	if maxDepth > 0 {
+		pcs = make([]uintptr, maxDepth+trimSlack+skip)
+		pcs = pcs[:runtime.Callers(2, pcs)]
+	} else {
+		pcs = make([]uintptr, 32)
+		for {
+			n := runtime.Callers(2, pcs)
+			if n < len(pcs) {
+				pcs = pcs[:n]
+				break
+			}
+			pcs = make([]uintptr, len(pcs)*2)
+		}
+	}
+	numErrsPCs := 0
+	for numErrsPCs < len(pcs) && isErrsPC(pcs[numErrsPCs]) {
+		numErrsPCs++
+	}
+	skipEnd := min(numErrsPCs+skip, len(pcs))
+	if noTrimStack.Load() {
+		pcs = append(pcs[:numErrsPCs:numErrsPCs], pcs[skipEnd:]...)
+	} else {
+		pcs = pcs[skipEnd:]
+	}
+	if maxDepth > 0 && len(pcs) > maxDepth {
+		pcs = pcs[:maxDepth]
+	}
+	return pcs
+}
+
+// Get the textual stack, which is rendered on first access and then cached.
+// The returned slice is shared, and must not be modified.
+func (e *err) cachedStack() []byte {
+	e.stackOnce.Do(func() {
+		e.stack = renderStack(e.StackFrames())
+	})
+	return e.stack
+}
+
+// Check if the stack of the next error should be
+// captured, according to SetStackSampleRate
+func sampleStack() bool {
+	rate := stackSampleRate.Load()
+	if rate == nil || *rate >= 1 {
+		return true
+	}
+	n := stackSampleCount.Add(1)
+	return uint64(float64(n)**rate) != uint64(float64(n-1)**rate)
+}
+
+// Get the stacks of all goroutines, growing the buffer until they fit
+func allStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+}
+
+// Check if the given return program counter is inside the errs package
+func isErrsPC(pc uintptr) bool {
+	fn := runtime.FuncForPC(pc - 1)
+	return fn != nil && strings.HasPrefix(fn.Name(), errsFuncPrefix)
+}
+
+// Get the package path of a fully qualified function name,
+// e.g "github.com/foo/bar" for "github.com/foo/bar.(*Baz).Qux"
+func funcPackage(funcName string) string {
+	lastSlash := strings.LastIndex(funcName, "/")
+	if dot := strings.Index(funcName[lastSlash+1:], "."); dot >= 0 {
+		return funcName[:lastSlash+1+dot]
+	}
+	return funcName
+}
+
+// Resolve the given program counters into Frames
+func resolveFrames(pcs []uintptr) []Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+	maxDepth := int(maxStackDepth.Load())
+	frames := make([]Frame, 0, len(pcs))
+	iter := runtime.CallersFrames(pcs)
+	for {
+		frame, more := iter.Next()
+		frames = append(frames, Frame{frame.Function, frame.File, frame.Line, frame.PC})
+		if !more || len(frames) == maxDepth {
+			return frames
+		}
+	}
+}
+
+// The marker which ends stacks truncated by SetMaxStackBytes
+const stackTruncatedMarker = "...[truncated]"
+
+// Render frames in a format similar to debug.Stack(),
+// truncated according to SetMaxStackBytes
+func renderStack(frames []Frame) []byte {
+	if len(frames) == 0 {
+		return nil
+	}
+	var buf strings.Builder
+	for _, frame := range frames {
+		fmt.Fprintf(&buf, "%s()\n\t%s:%d\n", frame.Func, frame.File, frame.Line)
+	}
+	return []byte(truncateStack(buf.String()))
+}
+
+// Truncate a rendered stack according to SetMaxStackBytes
+func truncateStack(stack string) string {
+	if maxBytes := int(maxStackBytes.Load()); maxBytes > 0 && len(stack) > maxBytes {
+		cut := max(0, maxBytes-len(stackTruncatedMarker))
+		stack = (stack[:cut] + stackTruncatedMarker)[:maxBytes]
+	}
+	return stack
+}
+
+// Truncate a stack rendered as one line per frame according to
+// SetMaxStackBytes. Whole lines are dropped, and the marker is appended
+// as the last line.
+func truncateStackLines(lines []string) []string {
+	maxBytes := int(maxStackBytes.Load())
+	if maxBytes <= 0 {
+		return lines
+	}
+	numBytes := 0
+	for _, line := range lines {
+		numBytes += len(line)
+	}
+	if numBytes <= maxBytes {
+		return lines
+	}
+	numBytes = len(stackTruncatedMarker)
+	for i, line := range lines {
+		if numBytes+len(line) > maxBytes {
+			return append(lines[:i:i], stackTruncatedMarker)
+		}
+		numBytes += len(line)
+	}
+	return lines
+}
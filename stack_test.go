@@ -0,0 +1,289 @@
+package errs_test
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestStackFrames(t *testing.T) {
+	err := errs.New(nil)
+	frames := err.StackFrames()
+	assert(t, len(frames) > 0, "Expected stack frames")
+	found := false
+	for _, frame := range frames {
+		if strings.HasSuffix(frame.Func, ".TestStackFrames") {
+			found = true
+			assert(t, strings.HasSuffix(frame.File, "stack_test.go"), "Expected frame file to be stack_test.go")
+			assert(t, frame.Line > 0, "Expected frame line")
+			assert(t, frame.PC != 0, "Expected frame PC")
+		}
+	}
+	assert(t, found, "Expected TestStackFrames in stack frames")
+	assert(t, len(err.Stack()) > 0, "Expected Stack to still work")
+}
+
+func TestMaxStackDepth(t *testing.T) {
+	errs.SetMaxStackDepth(5)
+	defer errs.SetMaxStackDepth(0)
+	err := errs.New(nil)
+	assert(t, len(err.StackFrames()) > 0, "Expected stack frames")
+	assert(t, len(err.StackFrames()) <= 5, "Expected at most 5 stack frames")
+	assert(t, strings.Count(string(err.Stack()), "\n\t") <= 5, "Expected at most 5 rendered stack frames")
+}
+
+func TestTrimStack(t *testing.T) {
+	frames := errs.New(nil).StackFrames()
+	assert(t, strings.HasSuffix(frames[0].Func, ".TestTrimStack"), "Expected top frame to be the test function", frames[0].Func)
+	frames = errs.Wrap(io.EOF, nil).StackFrames()
+	assert(t, strings.HasSuffix(frames[0].Func, ".TestTrimStack"), "Expected top frame to be the test function", frames[0].Func)
+
+	errs.SetTrimStack(false)
+	defer errs.SetTrimStack(true)
+	frames = errs.New(nil).StackFrames()
+	assert(t, strings.HasSuffix(frames[0].Func, "go-errs.New"), "Expected top frame to be errs.New", frames[0].Func)
+}
+
+func TestNewSkip(t *testing.T) {
+	frames := fail("Failed").StackFrames()
+	assert(t, strings.HasSuffix(frames[0].Func, ".TestNewSkip"), "Expected top frame to be the helper's caller", frames[0].Func)
+	assert(t, fail("Failed").PublicMsg() == "Failed")
+}
+
+func fail(msg string) errs.Err { return errs.NewSkip(1, nil, msg) }
+
+func TestNewSkipNegative(t *testing.T) {
+	frames := errs.NewSkip(-5, nil).StackFrames()
+	assert(t, strings.HasSuffix(frames[0].Func, ".TestNewSkipNegative"), "Expected a negative skip to be treated as 0", frames[0].Func)
+	errs.SetMaxStackDepth(2)
+	defer errs.SetMaxStackDepth(0)
+	frames = errs.NewSkip(-5, nil).StackFrames()
+	assert(t, len(frames) == 2 && strings.HasSuffix(frames[0].Func, ".TestNewSkipNegative"), frames)
+}
+
+func TestWrapSkip(t *testing.T) {
+	_, _, line, _ := runtime.Caller(0)
+	err := wrapInHelper(io.EOF)
+	assert(t, err.Location() == "stack_test.go:"+strconv.Itoa(line+1), "Expected the helper's caller", err.Location())
+
+	inner := errs.New(nil)
+	assert(t, wrapInHelper(inner).Location() == inner.Location(), "Expected merging wrap to keep the original stack")
+
+	errs.SetPreserveChain(true)
+	defer errs.SetPreserveChain(false)
+	err = wrapInHelper(inner)
+	assert(t, err.Location() == "stack_test.go:"+strconv.Itoa(line+9), "Expected the helper's caller with preserved chain", err.Location())
+}
+
+func wrapInHelper(err error) errs.Err { return errs.WrapSkip(1, err, nil) }
+
+func TestNoStack(t *testing.T) {
+	for _, err := range []errs.Err{errs.NewNoStack(nil, "msg"), errs.UserErrorNoStack(nil, "msg")} {
+		assert(t, err.Stack() == nil, "Expected no stack")
+		assert(t, len(err.StackFrames()) == 0, "Expected no stack frames")
+		assert(t, !strings.Contains(err.LogString(), "Stack:"), "Expected LogString to omit the stack")
+		assert(t, err.PublicMsg() == "msg")
+	}
+	assert(t, errs.UserErrorNoStack(nil).IsUserError())
+}
+
+func BenchmarkNewNoStack(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		errs.NewNoStack(nil)
+	}
+}
+
+func TestUserErrorStack(t *testing.T) {
+	err := errs.UserError(nil, "Wrong password")
+	assert(t, err.Stack() == nil, "Expected no user error stack by default", string(err.Stack()))
+	assert(t, err.IsUserError() && err.PublicMsg() == "Wrong password")
+	assert(t, errs.UserErrorWrap(io.EOF, nil).Stack() == nil, "Expected no UserErrorWrap stack by default")
+	assert(t, errs.Validation().Stack() == nil, "Expected no Validation stack by default")
+
+	errs.SetUserErrorStack(true)
+	defer errs.SetUserErrorStack(false)
+	for _, err := range []errs.Err{errs.UserError(nil, "Wrong password"), errs.UserErrorWrap(io.EOF, nil), errs.Validation()} {
+		assert(t, strings.Contains(string(err.Stack()), "TestUserErrorStack"), "Expected a stack with SetUserErrorStack(true)", string(err.Stack()))
+	}
+}
+
+func BenchmarkUserError(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		errs.UserError(nil, "Wrong password")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	var fingerprints []string
+	for i := 0; i < 2; i++ {
+		fingerprints = append(fingerprints, errs.New(errs.Info{"UserID": i}).Fingerprint())
+	}
+	assert(t, fingerprints[0] == fingerprints[1], "Expected same-site errors to have equal fingerprints")
+	assert(t, fingerprints[0] != "")
+
+	other := errs.New(nil).Fingerprint()
+	assert(t, other != fingerprints[0], "Expected errors from different sites to have different fingerprints")
+
+	var codeFingerprints []string
+	for _, code := range []string{"A", "B"} {
+		codeFingerprints = append(codeFingerprints, errs.NewWithCode(code, nil).Fingerprint())
+	}
+	assert(t, codeFingerprints[0] != codeFingerprints[1], "Expected errors with different codes to have different fingerprints")
+}
+
+func TestCallers(t *testing.T) {
+	pcs := errs.New(nil).Callers()
+	assert(t, len(pcs) > 0, "Expected program counters")
+	frame, _ := runtime.CallersFrames(pcs).Next()
+	assert(t, strings.HasSuffix(frame.Function, ".TestCallers"), "Expected first frame to be the test function", frame.Function)
+}
+
+func TestStackTrace(t *testing.T) {
+	st := errs.New(nil).StackTrace()
+	assert(t, len(st) > 0, "Expected stack trace frames")
+	frame := st[0]
+	assert(t, fmt.Sprintf("%s", frame) == "stack_test.go")
+	assert(t, fmt.Sprintf("%d", frame) == strconv.Itoa(frame.Line))
+	assert(t, fmt.Sprintf("%n", frame) == "TestStackTrace", fmt.Sprintf("%n", frame))
+	assert(t, fmt.Sprintf("%v", frame) == "stack_test.go:"+strconv.Itoa(frame.Line))
+	assert(t, fmt.Sprintf("%+v", frame) == frame.Func+"\n\t"+frame.File+":"+strconv.Itoa(frame.Line))
+	assert(t, strings.HasPrefix(fmt.Sprintf("%+v", st), "\n"+fmt.Sprintf("%+v", frame)))
+	assert(t, strings.HasPrefix(fmt.Sprintf("%v", st), "[stack_test.go:"))
+}
+
+func recurse(depth int) errs.Err {
+	if depth == 0 {
+		return errs.New(nil)
+	}
+	return recurse(depth - 1)
+}
+
+func TestMaxStackBytes(t *testing.T) {
+	errs.SetMaxStackBytes(1024)
+	defer errs.SetMaxStackBytes(0)
+	err := recurse(1000)
+	assert(t, len(err.Stack()) <= 1024, "Expected the stack to be truncated", len(err.Stack()))
+	assert(t, strings.HasSuffix(string(err.Stack()), "...[truncated]"), "Expected the truncation marker")
+	assert(t, len(err.StackFrames()) > 1000, "Expected all frames to be kept")
+
+	compact := err.LogStringCompact()
+	stack := compact[strings.Index(compact, " | Stack: ")+len(" | Stack: "):]
+	assert(t, len(stack) <= 1024, "Expected the compact stack to be truncated", len(stack))
+	assert(t, strings.HasSuffix(stack, "...[truncated]"), "Expected the truncation marker in the compact stack")
+
+	var lines []string
+	for _, attr := range err.(slog.LogValuer).LogValue().Group() {
+		if attr.Key == "stack" {
+			lines, _ = attr.Value.Any().([]string)
+		}
+	}
+	assert(t, len(lines) > 1 && len(lines) < 1000, "Expected the slog stack to be truncated", len(lines))
+	assert(t, len(strings.Join(lines, "")) <= 1024, "Expected the slog stack to be at most the limit", len(strings.Join(lines, "")))
+	assert(t, lines[len(lines)-1] == "...[truncated]", "Expected the truncation marker in the slog stack")
+}
+
+func TestStackLinks(t *testing.T) {
+	err := errs.New(nil)
+	frame := err.StackFrames()[0]
+	links := err.StackLinks()
+	assert(t, len(links) == len(err.StackFrames()), "Expected a link per frame")
+	assert(t, links[0] == frame.File+":"+strconv.Itoa(frame.Line), links[0])
+
+	errs.SetSourceLinkPrefix(path.Dir(frame.File), "https://github.com/marcuswestin/go-errs/blob/main/")
+	defer errs.SetSourceLinkPrefix("", "")
+	links = err.StackLinks()
+	assert(t, links[0] == "https://github.com/marcuswestin/go-errs/blob/main/stack_test.go#L"+strconv.Itoa(frame.Line), links[0])
+	assert(t, !strings.HasPrefix(links[len(links)-1], "https://"), "Expected frames outside of root to keep their path", links[len(links)-1])
+	assert(t, errs.NewNoStack(nil).StackLinks() == nil)
+}
+
+func TestAppFrames(t *testing.T) {
+	err := errs.New(nil)
+	frames := err.AppFrames("github.com/marcuswestin/go-errs")
+	assert(t, len(frames) > 0 && len(frames) < len(err.StackFrames()), "Expected some frames to be excluded", frames)
+	assert(t, strings.HasSuffix(frames[0].Func, ".TestAppFrames"), frames[0].Func)
+	for _, frame := range frames {
+		assert(t, !strings.HasPrefix(frame.Func, "testing.") && !strings.HasPrefix(frame.Func, "runtime."), "Expected no stdlib frames", frame.Func)
+	}
+	assert(t, errs.NewNoStack(nil).AppFrames("github.com/marcuswestin/go-errs") == nil)
+}
+
+func TestLocation(t *testing.T) {
+	_, _, line, _ := runtime.Caller(0)
+	err := errs.New(nil)
+	assert(t, err.Location() == "stack_test.go:"+strconv.Itoa(line+1), err.Location())
+	assert(t, errs.Wrap(err, nil).Location() == err.Location(), "Expected wrapping to keep the location")
+	assert(t, errs.NewNoStack(nil).Location() == "", "Expected no location without a stack")
+
+	errs.SetTrimStack(false)
+	defer errs.SetTrimStack(true)
+	assert(t, errs.New(nil).Location() == "stack_test.go:"+strconv.Itoa(line+8), "Expected errs frames to be skipped")
+}
+
+func TestNewAllStacks(t *testing.T) {
+	assert(t, errs.New(nil).AllStacks() == nil, "Expected no goroutine stacks by default")
+
+	block := make(chan struct{})
+	defer close(block)
+	go func() { <-block }()
+	err := errs.NewAllStacks(nil, "deadlock?")
+	allStacks := string(err.AllStacks())
+	assert(t, strings.Count(allStacks, "goroutine ") > 1, "Expected multiple goroutine stacks", allStacks)
+	assert(t, strings.Contains(allStacks, "TestNewAllStacks"), "Expected the current goroutine's stack")
+	assert(t, err.PublicMsg() == "deadlock?")
+	assert(t, errs.Wrap(err, nil).AllStacks() != nil, "Expected stacks to survive wrapping")
+}
+
+func TestSetStackSampleRate(t *testing.T) {
+	errs.SetStackSampleRate(0)
+	defer errs.SetStackSampleRate(1)
+	err := errs.New(nil, "unsampled")
+	assert(t, err.Stack() == nil, "Expected no stack at rate 0")
+	assert(t, !strings.Contains(err.LogString(), "Stack:"), "Expected LogString without stack")
+
+	errs.SetStackSampleRate(0.25)
+	numStacks := 0
+	for i := 0; i < 100; i++ {
+		if errs.New(nil).Stack() != nil {
+			numStacks++
+		}
+	}
+	assert(t, numStacks == 25, "Expected a quarter of errors to have stacks", numStacks)
+}
+
+func BenchmarkNewSampleRate0(b *testing.B) {
+	errs.SetStackSampleRate(0)
+	defer errs.SetStackSampleRate(1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		errs.New(nil)
+	}
+}
+
+func BenchmarkNewSampleRate1(b *testing.B) {
+	errs.SetStackSampleRate(1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		errs.New(nil)
+	}
+}
+
+func TestStackCopy(t *testing.T) {
+	err := errs.New(nil)
+	stack := err.Stack()
+	original := string(stack)
+	for i := range stack {
+		stack[i] = 'x'
+	}
+	assert(t, string(err.Stack()) == original, "Expected stored stack to be unchanged")
+	assert(t, strings.Contains(err.LogString(), original), "Expected LogString to use the stored stack")
+}
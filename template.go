@@ -0,0 +1,66 @@
+package errs
+
+import (
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"time"
+)
+
+// LogFields are the fields available to templates given to SetLogTemplate
+type LogFields struct {
+	Level        Level
+	Time         time.Time
+	GoroutineID  uint64 // 0 unless SetCaptureGoroutineID(true) was set
+	Code         string
+	WrappedError string // The error string of the wrapped error
+	Info         string // The rendered info, with sorted keys, e.g "a=1 b=2"
+	PublicMsg    string
+	Stack        string
+}
+
+// SetLogTemplate sets a text/template for LogString, with LogFields as
+// its data, e.g `errs.SetLogTemplate("{{.Time}} [{{.Code}}] {{.PublicMsg}} {{.Info}}")`.
+// The template is parsed and executed once with empty fields, and
+// SetLogTemplate panics if either fails. Passing an empty string
+// restores the default format.
+func SetLogTemplate(text string) {
+	if text == "" {
+		logTemplate.Store(nil)
+		return
+	}
+	tmpl := template.Must(template.New("errs.LogString").Parse(text))
+	if execErr := tmpl.Execute(&strings.Builder{}, LogFields{}); execErr != nil {
+		panic(execErr)
+	}
+	logTemplate.Store(tmpl)
+}
+
+// Internal
+///////////
+
+var logTemplate atomic.Pointer[template.Template]
+
+// Render the error with the given template. If the template
+// fails, the default format is returned instead.
+func (e *err) templateLogString(tmpl *template.Template) string {
+	v := e.view()
+	stack := v.cachedStack()
+	v.mu.RLock()
+	fields := LogFields{
+		Level:        v.levelLocked(),
+		Time:         v.time,
+		GoroutineID:  v.goroutineID,
+		Code:         v.code,
+		WrappedError: v.wrappedErrStr(),
+		Info:         filterInfo(v.info).String(),
+		PublicMsg:    v.publicMsgStr(),
+		Stack:        string(stack),
+	}
+	v.mu.RUnlock()
+	var b strings.Builder
+	if execErr := tmpl.Execute(&b, fields); execErr != nil {
+		return e.defaultLogString()
+	}
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package errs_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestSetLogTemplate(t *testing.T) {
+	errs.SetLogTemplate("[{{.Code}}] {{.PublicMsg}} {{.Info}}")
+	defer errs.SetLogTemplate("")
+	err := errs.WrapWithCode("CODE", errors.New("std"), errs.Info{"b": 2, "a": 1}, "publicMsg")
+	assert(t, err.LogString() == "[CODE] publicMsg a=1 b=2", err.LogString())
+	assert(t, err.Error() == err.LogString())
+
+	errs.SetLogTemplate("{{.Level}}: {{.WrappedError}}\n{{.Stack}}")
+	assert(t, strings.HasPrefix(err.LogString(), "Error: std\n"), err.LogString())
+	assert(t, strings.Contains(err.LogString(), "TestSetLogTemplate"), "Expected the stack")
+
+	errs.SetLogTemplate("")
+	assert(t, strings.Contains(err.LogString(), "| PublicMsg: publicMsg | Stack: "), "Expected the default format to be restored")
+
+	for _, invalid := range []string{"{{.Code", "{{.NoSuchField}}"} {
+		func() {
+			defer func() { assert(t, recover() != nil, "Expected invalid template to panic", invalid) }()
+			errs.SetLogTemplate(invalid)
+		}()
+	}
+	assert(t, strings.Contains(err.LogString(), "| PublicMsg: "), "Expected invalid templates not to be set")
+}
@@ -0,0 +1,202 @@
+package errs
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+)
+
+// Validation creates a new user error for form validation, which collects
+// a message per invalid field. It satisfies Err with IsUserError() true, e.g
+//
+//	err := errs.Validation()
+//	if !validEmail(req.Email) {
+//		err.Field("email", "is not a valid email")
+//	}
+//	if err.HasFields() { return err }
+//
+// The error is passed to OnError hooks when its first field is set, since
+// a ValidationErr without fields is not returned as an error. Like
+// errs.UserError, it does not capture a stack by default.
+func Validation() *ValidationErr {
+	return &ValidationErr{buildErr(userErrorCallers(), nil, true, Info{}, nil), map[string]string{}}
+}
+
+// ValidationErr is an Err with a message per invalid field. See Validation
+type ValidationErr struct {
+	*err
+	fields map[string]string // guarded by err.mu
+}
+
+// Field sets the message for the given field, and returns the error
+func (v *ValidationErr) Field(name, message string) *ValidationErr {
+	v.mu.Lock()
+	isFirst := len(v.fields) == 0
+	v.fields[name] = message
+	v.mu.Unlock()
+	if isFirst {
+		reportErr(v)
+	}
+	return v
+}
+
+// HasFields reports whether any field messages have been set
+func (v *ValidationErr) HasFields() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return len(v.fields) > 0
+}
+
+// Fields returns a copy of the messages per field
+func (v *ValidationErr) Fields() map[string]string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	fields := make(map[string]string, len(v.fields))
+	for name, message := range v.fields {
+		fields[name] = message
+	}
+	return fields
+}
+
+// FieldsJSON returns the messages per field as a JSON object for API
+// responses, e.g `{"email":"already taken","password":"too short"}`
+func (v *ValidationErr) FieldsJSON() ([]byte, error) {
+	return json.Marshal(v.Fields())
+}
+
+func (v *ValidationErr) Error() string  { return v.LogString() }
+func (v *ValidationErr) String() string { return v.LogString() }
+
+// Implements Err. The With methods of err are overridden
+// in order to return the ValidationErr rather than its base err.
+func (v *ValidationErr) WithRetryable(retryable bool) Err { v.err.WithRetryable(retryable); return v }
+func (v *ValidationErr) WithLevel(level Level) Err        { v.err.WithLevel(level); return v }
+func (v *ValidationErr) WithStatus(status int) Err        { v.err.WithStatus(status); return v }
+func (v *ValidationErr) WithInfo(key string, val interface{}) Err {
+	v.err.WithInfo(key, val)
+	return v
+}
+func (v *ValidationErr) WithInfoMap(info Info) Err   { v.err.WithInfoMap(info); return v }
+func (v *ValidationErr) WithRequestID(id string) Err { v.err.WithRequestID(id); return v }
+func (v *ValidationErr) WithTraceID(id string) Err   { v.err.WithTraceID(id); return v }
+
+// Implements Err
+func (v *ValidationErr) Clone() Err {
+	return &ValidationErr{v.err.clone(), v.Fields()}
+}
+
+// Implements Err
+func (v *ValidationErr) LogString() string {
+	return concatArgs(v.err.LogString(), "| Fields:", v.fieldsStr())
+}
+
+// Implements Err
+func (v *ValidationErr) LogStringCompact() string {
+	return concatArgs(v.err.LogStringCompact(), "| Fields:", escapeNewlines(v.fieldsStr()))
+}
+
+// MarshalJSON implements json.Marshaler, like err.MarshalJSON
+// with an additional "fields" object
+func (v *ValidationErr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		jsonErr
+		Fields map[string]string `json:"fields"`
+	}{v.err.toJSON(), v.Fields()})
+}
+
+// Implements Err. Like err.PublicJSON, with an additional "fields" object,
+// e.g `{"message":"","code":"","fields":{"email":"already taken"}}`
+func (v *ValidationErr) PublicJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		jsonPublicErr
+		Fields map[string]string `json:"fields"`
+	}{v.err.toPublicJSON(v.PublicMsg()), v.Fields()})
+}
+
+// Implements Err. Like err.ProblemJSON, with an additional "fields" member
+func (v *ValidationErr) ProblemJSON() ([]byte, error) {
+	problem := v.err.toProblem(v.PublicMsg())
+	problem["fields"] = v.Fields()
+	return json.Marshal(problem)
+}
+
+// Implements Err. The fields are kept, since they are meant for the user
+func (v *ValidationErr) Sanitize() Err {
+	return &ValidationErr{v.err.sanitize(v.PublicMsgParts()), v.Fields()}
+}
+
+// Implements Err. The fields must be equal as well
+func (v *ValidationErr) Equal(other Err) bool {
+	otherV, isValidation := other.(*ValidationErr)
+	return isValidation && v.err.equal(v.PublicMsg(), other) && reflect.DeepEqual(v.Fields(), otherV.Fields())
+}
+
+// LogValue implements slog.LogValuer, like err.LogValue
+// with an additional "fields" group
+func (v *ValidationErr) LogValue() slog.Value {
+	fields := slog.GroupValue(infoAttrs(v.fieldsInfo())...)
+	return slog.GroupValue(append(v.err.logAttrs(v.PublicMsg()), slog.Attr{Key: "fields", Value: fields})...)
+}
+
+// GobEncode implements gob.GobEncoder, like err.GobEncode
+// with the fields in addition
+func (v *ValidationErr) GobEncode() ([]byte, error) {
+	data, encodeErr := v.err.GobEncode()
+	if encodeErr != nil {
+		return nil, encodeErr
+	}
+	var buf bytes.Buffer
+	if encodeErr := gob.NewEncoder(&buf).Encode(gobValidationErr{data, v.Fields()}); encodeErr != nil {
+		return nil, encodeErr
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder, for errors encoded with GobEncode
+func (v *ValidationErr) GobDecode(data []byte) error {
+	var gobV gobValidationErr
+	if decodeErr := gob.NewDecoder(bytes.NewReader(data)).Decode(&gobV); decodeErr != nil {
+		return decodeErr
+	}
+	v.err = &err{}
+	if decodeErr := v.err.GobDecode(gobV.Err); decodeErr != nil {
+		return decodeErr
+	}
+	v.fields = gobV.Fields
+	if v.fields == nil {
+		v.fields = map[string]string{}
+	}
+	return nil
+}
+
+// Implements fmt.Formatter. See err.Format
+func (v *ValidationErr) Format(s fmt.State, verb rune) {
+	formatErr(s, verb, concatArgs(v.err.summary(), "| Fields:", v.fieldsStr()), v.cachedStack())
+}
+
+// Internal
+///////////
+
+// gobValidationErr is the gob representation of a ValidationErr
+type gobValidationErr struct {
+	Err    []byte
+	Fields map[string]string
+}
+
+// Render the fields for logging, in sorted order
+func (v *ValidationErr) fieldsStr() string {
+	return v.fieldsInfo().String()
+}
+
+// Get the fields as Info, for rendering
+func (v *ValidationErr) fieldsInfo() Info {
+	fields := v.Fields()
+	info := make(Info, len(fields))
+	for name, message := range fields {
+		info[name] = message
+	}
+	return info
+}
@@ -0,0 +1,89 @@
+package errs_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/marcuswestin/go-errs"
+)
+
+func TestValidation(t *testing.T) {
+	v := errs.Validation()
+	assert(t, !v.HasFields(), "Expected no fields initially")
+	v.Field("email", "already taken").Field("password", "too short")
+	assert(t, v.HasFields())
+
+	fields := v.Fields()
+	assert(t, len(fields) == 2 && fields["email"] == "already taken" && fields["password"] == "too short", fields)
+	fields["email"] = "modified"
+	assert(t, v.Fields()["email"] == "already taken", "Expected Fields to return a copy")
+
+	data, marshalErr := v.FieldsJSON()
+	assert(t, marshalErr == nil)
+	assert(t, string(data) == `{"email":"already taken","password":"too short"}`, string(data))
+
+	var err errs.Err = v
+	assert(t, err.IsUserError(), "Expected a user error")
+	assert(t, err.HTTPStatus() == 400)
+	assert(t, strings.Contains(err.LogString(), "| Fields: email=already taken password=too short"), err.LogString())
+	assert(t, err.WithInfo("Foo", "Bar") == err, "Expected WithInfo to return the ValidationErr")
+	assert(t, errs.Wrap(err, nil, "Invalid form") == err, "Expected Wrap to merge into the ValidationErr")
+	assert(t, err.Stack() == nil, "Expected no stack by default", string(err.Stack()))
+}
+
+func TestValidationReport(t *testing.T) {
+	var reported []errs.Err
+	testHook = func(err errs.Err) { reported = append(reported, err) }
+	defer func() { testHook = nil }()
+	v := errs.Validation()
+	assert(t, len(reported) == 0, "Expected no report for a ValidationErr without fields")
+	v.Field("email", "already taken").Field("password", "too short")
+	assert(t, len(reported) == 1 && reported[0] == v, "Expected one report when the first field is set", len(reported))
+}
+
+func TestValidationEncoding(t *testing.T) {
+	v := errs.Validation().Field("email", "taken")
+
+	data, _ := json.Marshal(v)
+	var res struct {
+		Fields      map[string]string `json:"fields"`
+		IsUserError bool              `json:"isUserError"`
+	}
+	assert(t, json.Unmarshal(data, &res) == nil && res.Fields["email"] == "taken" && res.IsUserError, string(data))
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "err", v)
+	assert(t, strings.Contains(buf.String(), `"fields":{"email":"taken"}`), buf.String())
+
+	type reply struct{ Err errs.Err }
+	buf.Reset()
+	assert(t, gob.NewEncoder(&buf).Encode(reply{v}) == nil, "Expected encode to succeed")
+	var decoded reply
+	decodeErr := gob.NewDecoder(&buf).Decode(&decoded)
+	assert(t, decodeErr == nil, "Expected decode to succeed", decodeErr)
+	decodedV, isValidation := decoded.Err.(*errs.ValidationErr)
+	assert(t, isValidation && decodedV.Fields()["email"] == "taken" && decodedV.IsUserError(), decoded.Err)
+}
+
+func TestValidationPublic(t *testing.T) {
+	v := errs.Validation().Field("email", "taken")
+	v.WithInfo("Password", "hunter2")
+
+	data, _ := v.PublicJSON()
+	assert(t, strings.Contains(string(data), `"fields":{"email":"taken"}`), string(data))
+	data, _ = v.ProblemJSON()
+	assert(t, strings.Contains(string(data), `"fields":{"email":"taken"}`), string(data))
+
+	sanitized, isValidation := v.Sanitize().(*errs.ValidationErr)
+	assert(t, isValidation && sanitized.Fields()["email"] == "taken", "Expected Sanitize to keep the fields", sanitized)
+	assert(t, sanitized.Info("Password") == nil, "Expected Sanitize to drop the info")
+	sanitized.Field("email", "modified")
+	assert(t, v.Fields()["email"] == "taken", "Expected Sanitize to copy the fields")
+
+	assert(t, v.Equal(v.Clone()), "Expected a clone to be equal")
+	assert(t, !v.Equal(errs.Validation().Field("email", "invalid")), "Expected different fields not to be equal")
+}